@@ -0,0 +1,140 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/nikhilpratapgit/TodoApp/blob"
+	"github.com/nikhilpratapgit/TodoApp/blob/blobtest"
+)
+
+func TestS3StoreRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		body        []byte
+		contentType string
+	}{
+		{name: "plain key", key: "todos/1/a", body: []byte("hello"), contentType: "text/plain"},
+		{name: "key needing escaping", key: "todos/1/my file+(1)~ä.txt", body: []byte("escaped"), contentType: "text/plain"},
+		{name: "empty body", key: "todos/1/empty", body: []byte{}},
+		{name: "binary body", key: "todos/2/bin", body: []byte{0, 1, 2, 255}, contentType: "application/octet-stream"},
+	}
+
+	server := blobtest.NewS3Server("attachments")
+	defer server.Close()
+	store := server.Store()
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := store.Put(ctx, tt.key, bytes.NewReader(tt.body), int64(len(tt.body)), tt.contentType); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			if !slices.Contains(server.Keys(), tt.key) {
+				t.Fatalf("server keys %v do not contain %q", server.Keys(), tt.key)
+			}
+
+			content, err := store.Get(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			got, err := io.ReadAll(content)
+			content.Close()
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if !bytes.Equal(got, tt.body) {
+				t.Errorf("Get body = %q, want %q", got, tt.body)
+			}
+
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := store.Get(ctx, tt.key); !errors.Is(err, blob.ErrNotFound) {
+				t.Errorf("Get after Delete error = %v, want %v", err, blob.ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestS3StoreErrors(t *testing.T) {
+	server := blobtest.NewS3Server("attachments")
+	defer server.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		config blob.S3Config
+		run    func(store *blob.S3Store) error
+		want   error
+	}{
+		{
+			name: "missing key",
+			run: func(store *blob.S3Store) error {
+				_, err := store.Get(ctx, "todos/missing")
+				return err
+			},
+			want: blob.ErrNotFound,
+		},
+		{
+			name: "deleting a missing key succeeds",
+			run: func(store *blob.S3Store) error {
+				return store.Delete(ctx, "todos/missing")
+			},
+		},
+		{
+			name:   "wrong secret is rejected",
+			config: blob.S3Config{SecretKey: "wrong"},
+			run: func(store *blob.S3Store) error {
+				return store.Put(ctx, "todos/1/a", bytes.NewReader([]byte("x")), 1, "text/plain")
+			},
+			want: errAny,
+		},
+		{
+			name:   "unknown bucket is rejected",
+			config: blob.S3Config{Bucket: "other"},
+			run: func(store *blob.S3Store) error {
+				return store.Put(ctx, "todos/1/a", bytes.NewReader([]byte("x")), 1, "text/plain")
+			},
+			want: errAny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := blob.S3Config{
+				Endpoint:  server.URL,
+				Region:    server.Region,
+				Bucket:    server.Bucket,
+				AccessKey: server.AccessKey,
+				SecretKey: server.SecretKey,
+			}
+			if tt.config.SecretKey != "" {
+				config.SecretKey = tt.config.SecretKey
+			}
+			if tt.config.Bucket != "" {
+				config.Bucket = tt.config.Bucket
+			}
+			store := blob.NewS3Store(config)
+			store.HTTPClient = server.Client()
+
+			err := tt.run(store)
+			switch {
+			case tt.want == errAny:
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+			case !errors.Is(err, tt.want):
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// errAny marks cases that only need to fail, whatever the error.
+var errAny = errors.New("any error")
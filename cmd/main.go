@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	// user timezones must resolve even where the host has no zoneinfo
+	_ "time/tzdata"
 
+	"github.com/nikhilpratapgit/TodoApp/blob"
 	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/mailer"
+	"github.com/nikhilpratapgit/TodoApp/notify"
+	"github.com/nikhilpratapgit/TodoApp/oidc"
 	"github.com/nikhilpratapgit/TodoApp/server"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+	"github.com/nikhilpratapgit/TodoApp/worker"
 )
 
 func main() {
@@ -19,9 +30,53 @@ func main() {
 		"postgres",
 		"local",
 		"local",
+		os.Getenv("DB_APP_PASSWORD"),
+		os.Getenv("DB_WORKER_PASSWORD"),
 		database.SSLModeDisable); err != nil {
-		fmt.Printf("Failed while initialize and migrate database: %v", err)
+		log.Fatalf("Failed while initialize and migrate database: %v", err)
 	}
+
+	// signed attachment links must not be forgeable with the JWT secret
+	if key := os.Getenv("URL_SIGNING_KEY"); key == "" || key == os.Getenv("JWT_SECRET_KEY") {
+		log.Fatal("URL_SIGNING_KEY must be set and differ from JWT_SECRET_KEY")
+	}
+
+	if adminEmail := os.Getenv("ADMIN_EMAIL"); adminEmail != "" {
+		if err := dbHelper.PromoteAdminByEmail(adminEmail); err != nil {
+			log.Fatalf("Failed to promote admin: %v", err)
+		}
+	}
+
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	mailer.Default = mail
+
+	store, err := blob.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure blob store: %v", err)
+	}
+	blob.Default = store
+
+	providers, err := oidc.LoadProvidersFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure identity providers: %v", err)
+	}
+	oidc.Providers = providers
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.StartSessionSweeper(ctx, utils.GetEnvDuration("SESSION_SWEEP_INTERVAL", utils.DefaultSessionSweepInterval))
+	worker.StartAccountPurger(ctx, utils.GetEnvDuration("ACCOUNT_PURGE_INTERVAL", utils.DefaultAccountPurgeInterval))
+	worker.StartReminderDispatcher(ctx, utils.GetEnvDuration("REMINDER_INTERVAL", utils.DefaultReminderInterval),
+		notify.FromEnv(mail), worker.ReminderConfig{
+			BatchSize:   utils.GetEnvInt("REMINDER_BATCH_SIZE", utils.DefaultReminderBatchSize),
+			MaxAttempts: utils.GetEnvInt("REMINDER_MAX_ATTEMPTS", utils.DefaultReminderMaxAttempts),
+			Backoff:     utils.GetEnvDuration("REMINDER_RETRY_BACKOFF", utils.DefaultReminderBackoff),
+			Lease:       utils.GetEnvDuration("REMINDER_LEASE", utils.DefaultReminderLease),
+		})
+
 	fmt.Println("server is running")
 	ServerErr := http.ListenAndServe(":8080", srv)
 	if ServerErr != nil {
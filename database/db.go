@@ -8,33 +8,69 @@ import (
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var (
-	Todo *sqlx.DB
+	Todo   *sqlx.DB
+	Worker *sqlx.DB
 )
 
 const (
 	SSLModeDisable SSLMode = "disable"
 )
 
+// roles created by 00014_workspaces
+const (
+	AppRole    = "todo_app"
+	WorkerRole = "todo_worker"
+)
+
 type SSLMode string
 
-func ConnectandMigrate(host, port, databaseName, user, password string, sslMode SSLMode) error {
+// ConnectandMigrate migrates as the owner, then connects as AppRole and WorkerRole.
+func ConnectandMigrate(host, port, databaseName, user, password, appPassword, workerPassword string, sslMode SSLMode) error {
+	if appPassword == "" || workerPassword == "" {
+		return errors.New("database passwords for the app and worker roles are required")
+	}
+
+	owner, err := connect(host, port, databaseName, user, password, sslMode)
+	if err != nil {
+		return err
+	}
+	defer owner.Close()
+	if err := migrateUp(owner); err != nil {
+		return err
+	}
+	for role, rolePassword := range map[string]string{AppRole: appPassword, WorkerRole: workerPassword} {
+		// ALTER ROLE takes no bind parameters
+		if _, err := owner.Exec(`ALTER ROLE ` + pq.QuoteIdentifier(role) + ` WITH LOGIN PASSWORD ` + pq.QuoteLiteral(rolePassword)); err != nil {
+			return fmt.Errorf("failed to set password for %s: %w", role, err)
+		}
+	}
+
+	if Todo, err = connect(host, port, databaseName, AppRole, appPassword, sslMode); err != nil {
+		return err
+	}
+	if Worker, err = connect(host, port, databaseName, WorkerRole, workerPassword, sslMode); err != nil {
+		return err
+	}
+	fmt.Println("Database connected successfully")
+	return nil
+}
+
+func connect(host, port, databaseName, user, password string, sslMode SSLMode) (*sqlx.DB, error) {
 	connectionStr := fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=%s", host, port, databaseName, user, password, sslMode)
 
 	DB, err := sqlx.Open("postgres", connectionStr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = DB.Ping()
 	if err != nil {
-		return fmt.Errorf("database ping failed %w", err)
+		return nil, fmt.Errorf("database ping failed %w", err)
 	}
-	fmt.Println("Database connected successfully")
-	Todo = DB
-	return migrateUp(DB)
+	return DB, nil
 }
 
 func migrateUp(db *sqlx.DB) error {
@@ -72,22 +108,42 @@ func migrateUp(db *sqlx.DB) error {
 //	func ShutdownDatabase() error {
 //		return Todo.Close()
 //	}
-//func Tx(fn func(tx *sqlx.Tx) error) error {
-//	tx, err := Todo.Beginx()
-//	if err != nil {
-//		return fmt.Errorf("failed to start a transaction: %v", err)
-//	}
-//	defer func() {
-//		if err != nil {
-//			if rollBackErr := tx.Rollback(); rollBackErr != nil {
-//				fmt.Println("failed to rollback tx : %s", rollBackErr)
-//			}
-//			return
-//		}
-//		if commitErr := tx.Commit(); commitErr != nil {
-//			fmt.Println("failed to commit: %s", commitErr)
-//		}
-//	}()
-//	err = fn(tx)
-//	return err
-//}
+func Tx(fn func(tx *sqlx.Tx) error) error {
+	return txOn(Todo, fn)
+}
+
+func txOn(db *sqlx.DB, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to start a transaction: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollBackErr := tx.Rollback(); rollBackErr != nil {
+				fmt.Printf("failed to rollback tx : %s\n", rollBackErr)
+			}
+			return
+		}
+		if commitErr := tx.Commit(); commitErr != nil {
+			fmt.Printf("failed to commit: %s\n", commitErr)
+			err = commitErr
+		}
+	}()
+	err = fn(tx)
+	return err
+}
+
+// WithWorkspace runs fn with row-level security scoped to workspaceID.
+func WithWorkspace(workspaceID string, fn func(tx *sqlx.Tx) error) error {
+	return Tx(func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`SELECT set_config('app.workspace_id', $1, true);`, workspaceID); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// WithoutTenant runs fn on the Worker connection, which bypasses row-level security.
+func WithoutTenant(fn func(tx *sqlx.Tx) error) error {
+	return txOn(Worker, fn)
+}
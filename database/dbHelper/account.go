@@ -0,0 +1,216 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var ErrEmailTaken = errors.New("email already in use")
+
+func GetUserPasswordHash(userID string) (string, error) {
+	SQL := `SELECT password
+			FROM users
+			WHERE id = $1
+			AND archived_at IS NULL;`
+
+	var password string
+	err := database.Todo.Get(&password, SQL, userID)
+	return password, err
+}
+func UpdateUserPassword(userID, hashedPassword string) error {
+	SQL := `UPDATE users
+			SET password = $1
+			WHERE id = $2
+			AND archived_at IS NULL;`
+
+	_, err := database.Todo.Exec(SQL, hashedPassword, userID)
+	return err
+}
+func UpdateUserEmail(userID, email string) error {
+	SQL := `UPDATE users
+			SET email = TRIM(LOWER($1)),
+			    verified_at = NULL
+			WHERE id = $2
+			AND archived_at IS NULL;`
+
+	_, err := database.Todo.Exec(SQL, email, userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "unique_user" {
+		return ErrEmailTaken
+	}
+	return err
+}
+func UpdateUserTimezone(userID, timezone string) error {
+	SQL := `UPDATE users
+			SET timezone = $1
+			WHERE id = $2
+			AND archived_at IS NULL;`
+
+	_, err := database.Todo.Exec(SQL, timezone, userID)
+	return err
+}
+func DeleteAccount(userID string, gracePeriod time.Duration) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		result, err := tx.Exec(`UPDATE users
+				SET archived_at = NOW(),
+				    purge_at = $2
+				WHERE id = $1
+				AND archived_at IS NULL;`, userID, time.Now().Add(gracePeriod))
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+		_, err = tx.Exec(`UPDATE user_session
+				SET archived_at = NOW()
+				WHERE user_id = $1
+				AND archived_at IS NULL;`, userID)
+		return err
+	})
+}
+
+// PurgeDeletedAccounts erases accounts past their grace period and returns the storage keys of their attachments.
+func PurgeDeletedAccounts() (int64, []string, error) {
+	var purged int64
+	storageKeys := make([]string, 0)
+	err := database.WithoutTenant(func(tx *sqlx.Tx) error {
+		var userIDs []string
+		if err := tx.Select(&userIDs, `SELECT id
+				FROM users
+				WHERE archived_at IS NOT NULL
+				AND purge_at <= NOW()
+				AND purged_at IS NULL
+				FOR UPDATE SKIP LOCKED;`); err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		users := pq.Array(userIDs)
+
+		// shared lists go to their longest-standing editor, or viewer, the rest are archived
+		if _, err := tx.Exec(`WITH heirs AS (
+					SELECT DISTINCT ON (m.list_id) m.list_id, m.user_id
+					FROM list_members m
+					JOIN lists l ON l.id = m.list_id
+					JOIN users u ON u.id = m.user_id AND u.archived_at IS NULL
+					WHERE l.owner_id = ANY($1)
+					AND NOT l.is_personal
+					AND l.archived_at IS NULL
+					AND m.user_id <> ALL($1)
+					ORDER BY m.list_id, m.role = 'editor' DESC, m.created_at
+				), moved AS (
+					UPDATE lists l
+					SET owner_id = h.user_id
+					FROM heirs h
+					WHERE l.id = h.list_id
+					RETURNING l.id, l.owner_id
+				)
+				UPDATE list_members m
+				SET role = 'owner'
+				FROM moved
+				WHERE m.list_id = moved.id
+				AND m.user_id = moved.owner_id;`, users); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE lists
+				SET archived_at = COALESCE(archived_at, NOW())
+				WHERE owner_id = ANY($1);`, users); err != nil {
+			return err
+		}
+
+		if err := tx.Select(&storageKeys, `SELECT a.storage_key
+				FROM attachments a
+				JOIN todos t ON t.id = a.todo_id
+				WHERE a.user_id = ANY($1)
+				OR t.user_id = ANY($1);`, users); err != nil {
+			return err
+		}
+		for _, statement := range []string{
+			`DELETE FROM todos WHERE user_id = ANY($1);`,
+			`DELETE FROM attachments WHERE user_id = ANY($1);`,
+			`DELETE FROM comments WHERE user_id = ANY($1);`,
+			`DELETE FROM comment_mentions WHERE user_id = ANY($1);`,
+			`UPDATE todos SET assignee_id = NULL WHERE assignee_id = ANY($1);`,
+			`DELETE FROM tags WHERE owner_id = ANY($1);`,
+			`DELETE FROM identities WHERE user_id = ANY($1);`,
+			`DELETE FROM api_keys WHERE user_id = ANY($1);`,
+			`UPDATE list_invitations SET revoked_at = NOW()
+				WHERE invited_by = ANY($1) AND accepted_at IS NULL AND revoked_at IS NULL;`,
+			`DELETE FROM list_members WHERE user_id = ANY($1);`,
+			`DELETE FROM workspace_members WHERE user_id = ANY($1);`,
+			`DELETE FROM reminders WHERE user_id = ANY($1);`,
+			`DELETE FROM notifications WHERE user_id = ANY($1);`,
+			`UPDATE notifications SET actor_id = NULL WHERE actor_id = ANY($1);`,
+			`UPDATE users SET purged_at = NOW() WHERE id = ANY($1);`,
+		} {
+			if _, err := tx.Exec(statement, users); err != nil {
+				return err
+			}
+		}
+		purged = int64(len(userIDs))
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return purged, storageKeys, nil
+}
+func GetUserProfile(userID string) (*models.UserProfile, error) {
+	SQL := `SELECT id, name, email, timezone, created_at, verified_at
+			FROM users
+			WHERE id = $1
+			AND archived_at IS NULL;`
+
+	var profile models.UserProfile
+	err := database.Todo.Get(&profile, SQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+func GetAllUserTodos(userID string) ([]models.Todos, error) {
+	SQL := `SELECT id,user_id,name,description,complete,expiring_at,created_at
+			FROM todos
+			WHERE user_id = $1
+			ORDER BY created_at;`
+
+	todos := make([]models.Todos, 0)
+	err := database.WithoutTenant(func(tx *sqlx.Tx) error {
+		return tx.Select(&todos, SQL, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+func GetSessionHistory(userID string) ([]models.SessionHistory, error) {
+	SQL := `SELECT id,
+			       created_at,
+			       last_seen_at,
+			       expires_at,
+			       archived_at,
+			       user_agent,
+			       client_ip
+			FROM user_session
+			WHERE user_id = $1
+			ORDER BY created_at;`
+
+	sessions := make([]models.SessionHistory, 0)
+	err := database.Todo.Select(&sessions, SQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
@@ -0,0 +1,158 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+const adminUserColumns = `u.id,
+			       u.name,
+			       u.email,
+			       u.role,
+			       u.created_at,
+			       u.verified_at,
+			       u.archived_at,
+			       (SELECT count(*) FROM todos t WHERE t.user_id = u.id) AS todo_count,
+			       (SELECT count(*) FROM todos t WHERE t.user_id = u.id AND t.complete) AS completed_todo_count`
+
+var ErrAccountPurged = errors.New("account has been purged")
+
+// likeEscaper makes a search term match literally inside a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+func ListUsers(search string, archived bool, limit, offset int) ([]models.AdminUser, error) {
+	SQL := `SELECT ` + adminUserColumns + `
+			FROM users u
+			WHERE ($1 = '' OR u.email LIKE '%' || LOWER($1) || '%' ESCAPE '\' OR u.name ILIKE '%' || $1 || '%' ESCAPE '\')
+			AND ((u.archived_at IS NOT NULL) = $2)
+			ORDER BY u.created_at DESC
+			LIMIT $3 OFFSET $4;`
+
+	users := make([]models.AdminUser, 0)
+	err := database.WithoutTenant(func(tx *sqlx.Tx) error {
+		return tx.Select(&users, SQL, escapeLike(search), archived, limit, offset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+func GetAdminUser(userID string) (*models.AdminUser, error) {
+	SQL := `SELECT ` + adminUserColumns + `
+			FROM users u
+			WHERE u.id = $1;`
+
+	var user models.AdminUser
+	err := database.WithoutTenant(func(tx *sqlx.Tx) error {
+		return tx.Get(&user, SQL, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+func ArchiveUser(userID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		result, err := tx.Exec(`UPDATE users
+				SET archived_at = NOW()
+				WHERE id = $1
+				AND archived_at IS NULL;`, userID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+		_, err = tx.Exec(`UPDATE user_session
+				SET archived_at = NOW()
+				WHERE user_id = $1
+				AND archived_at IS NULL;`, userID)
+		return err
+	})
+}
+func UnarchiveUser(userID string) error {
+	SQL := `UPDATE users
+			SET archived_at = NULL,
+			    purge_at = NULL
+			WHERE id = $1
+			AND archived_at IS NOT NULL
+			AND purged_at IS NULL;`
+
+	result, err := database.Todo.Exec(SQL, userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "unique_user" {
+		return ErrEmailTaken
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		var purged bool
+		err := database.Todo.Get(&purged, `SELECT purged_at IS NOT NULL FROM users WHERE id = $1;`, userID)
+		if err != nil {
+			return err
+		}
+		if purged {
+			return ErrAccountPurged
+		}
+		return sql.ErrNoRows
+	}
+	return nil
+}
+func SetUserRole(userID, role string) error {
+	SQL := `UPDATE users
+			SET role = $1
+			WHERE id = $2;`
+
+	result, err := database.Todo.Exec(SQL, role, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+func PromoteAdminByEmail(email string) error {
+	SQL := `UPDATE users
+			SET role = 'admin'
+			WHERE email = TRIM(LOWER($1))
+			AND archived_at IS NULL;`
+
+	_, err := database.Todo.Exec(SQL, email)
+	return err
+}
+func RevokeAllSessions(userID string) (int64, error) {
+	SQL := `UPDATE user_session
+			SET archived_at = NOW()
+			WHERE user_id = $1
+			AND archived_at IS NULL;`
+
+	result, err := database.Todo.Exec(SQL, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
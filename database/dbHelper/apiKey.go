@@ -0,0 +1,105 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+func CreateAPIKey(userID, name string, scopes []string, expiresAt *time.Time) (*models.CreatedAPIKey, error) {
+	secret, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+	prefixPart, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+	prefix := models.APIKeyPrefix + prefixPart[:8]
+	key := prefix + "_" + secret
+
+	SQL := `INSERT INTO api_keys(user_id, name, prefix, key_hash, scopes, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, name, prefix, scopes, expires_at, last_used_at, created_at;`
+
+	created := &models.CreatedAPIKey{Key: key}
+	err = database.Todo.Get(&created.APIKey, SQL, userID, name, prefix, utils.HashToken(key), pq.Array(scopes), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+func GetAPIKeys(userID string) ([]models.APIKey, error) {
+	SQL := `SELECT id, name, prefix, scopes, expires_at, last_used_at, created_at
+			FROM api_keys
+			WHERE user_id = $1
+			AND revoked_at IS NULL
+			ORDER BY created_at DESC;`
+
+	keys := make([]models.APIKey, 0)
+	err := database.Todo.Select(&keys, SQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+func RevokeAPIKey(userID, keyID string) error {
+	SQL := `UPDATE api_keys
+			SET revoked_at = NOW()
+			WHERE id = $1
+			AND user_id = $2
+			AND revoked_at IS NULL;`
+
+	result, err := database.Todo.Exec(SQL, keyID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+func RevokeAllAPIKeys(userID string) (int64, error) {
+	SQL := `UPDATE api_keys
+			SET revoked_at = NOW()
+			WHERE user_id = $1
+			AND revoked_at IS NULL;`
+
+	result, err := database.Todo.Exec(SQL, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+func ValidateAPIKey(key string) (*models.APIKeyAuth, error) {
+	SQL := `UPDATE api_keys k
+			SET last_used_at = NOW()
+			FROM users u
+			WHERE u.id = k.user_id
+			AND u.archived_at IS NULL
+			AND k.key_hash = $1
+			AND k.revoked_at IS NULL
+			AND (k.expires_at IS NULL OR k.expires_at > NOW())
+			RETURNING k.id, k.user_id, k.scopes, u.role;`
+
+	var auth models.APIKeyAuth
+	err := database.Todo.Get(&auth, SQL, utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	return &auth, nil
+}
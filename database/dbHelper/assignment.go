@@ -0,0 +1,113 @@
+package dbHelper
+
+import (
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var ErrInvalidAssignee = errors.New("assignee does not have access to this todo")
+
+func requireAssignable(q sqlx.Queryer, listID, assigneeID string) error {
+	SQL := `SELECT EXISTS(
+				SELECT 1
+				FROM list_members m
+				JOIN users u ON u.id = m.user_id
+				WHERE m.list_id = $1
+				AND m.user_id = $2
+				AND u.archived_at IS NULL
+			);`
+
+	var ok bool
+	if err := sqlx.Get(q, &ok, SQL, listID, assigneeID); err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidAssignee
+	}
+	return nil
+}
+func recordAssignment(tx *sqlx.Tx, todoID, assignedBy string, previousAssigneeID, assigneeID *string) error {
+	_, err := tx.Exec(`INSERT INTO todo_assignments(todo_id, assigned_by, previous_assignee_id, assignee_id)
+			VALUES ($1, $2, $3, $4);`, todoID, assignedBy, previousAssigneeID, assigneeID)
+	if err != nil || assigneeID == nil || *assigneeID == assignedBy {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO notifications(user_id, actor_id, kind, title, body, todo_id)
+			SELECT $3, a.id, $4, 'Assigned: ' || t.name, a.name || ' assigned you ' || t.name, t.id
+			FROM todos t
+			JOIN users a ON a.id = $2
+			WHERE t.id = $1;`, todoID, assignedBy, *assigneeID, models.NotificationAssignment)
+	return err
+}
+
+// AssignTodo sets or, with a nil assigneeID, clears the assignee of a todo.
+func AssignTodo(userID, workspaceID, todoID string, assigneeID *string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var current struct {
+			ListID     string  `db:"list_id"`
+			AssigneeID *string `db:"assignee_id"`
+		}
+		if err := tx.Get(&current, `SELECT list_id, assignee_id
+				FROM todos
+				WHERE id = $1
+				FOR UPDATE;`, todoID); err != nil {
+			return err
+		}
+		if current.AssigneeID == nil && assigneeID == nil ||
+			current.AssigneeID != nil && assigneeID != nil && *current.AssigneeID == *assigneeID {
+			return nil
+		}
+		if assigneeID != nil {
+			if err := requireAssignable(tx, current.ListID, *assigneeID); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(`UPDATE todos SET assignee_id = $1 WHERE id = $2;`, assigneeID, todoID); err != nil {
+			return err
+		}
+		return recordAssignment(tx, todoID, userID, current.AssigneeID, assigneeID)
+	})
+}
+
+// unassignMember clears the assignments of a member who lost access to a
+// list, or to every list of the workspace when listID is empty.
+func unassignMember(tx *sqlx.Tx, userID, workspaceID, listID, memberID string) error {
+	SQL := `WITH unassigned AS (
+				UPDATE todos
+				SET assignee_id = NULL
+				WHERE workspace_id = $1
+				AND assignee_id = $2
+				AND ($3::UUID IS NULL OR list_id = $3)
+				RETURNING id
+			)
+			INSERT INTO todo_assignments(todo_id, assigned_by, previous_assignee_id)
+			SELECT id, $4, $2
+			FROM unassigned;`
+
+	_, err := tx.Exec(SQL, workspaceID, memberID, nullIfEmpty(listID), userID)
+	return err
+}
+func GetTodoAssignments(userID, workspaceID, todoID string) ([]models.TodoAssignment, error) {
+	SQL := `SELECT id, todo_id, assigned_by, previous_assignee_id, assignee_id, created_at
+			FROM todo_assignments
+			WHERE todo_id = $1
+			ORDER BY created_at DESC;`
+
+	assignments := make([]models.TodoAssignment, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		return tx.Select(&assignments, SQL, todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
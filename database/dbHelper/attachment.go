@@ -0,0 +1,117 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+const attachmentColumns = `a.id, a.todo_id, a.user_id, a.file_name, a.content_type, a.size_bytes, a.storage_key, a.created_at`
+
+func CreateAttachment(userID, workspaceID string, attachment *models.Attachment) error {
+	SQL := `INSERT INTO attachments(id, todo_id, user_id, file_name, content_type, size_bytes, storage_key)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at;`
+
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, attachment.TodoID, userID, workspaceID); err != nil {
+			return err
+		}
+		return tx.Get(&attachment.CreatedAt, SQL, attachment.ID, attachment.TodoID, userID,
+			attachment.FileName, attachment.ContentType, attachment.SizeBytes, attachment.StorageKey)
+	})
+}
+func GetAttachments(userID, workspaceID, todoID string) ([]models.Attachment, error) {
+	SQL := `SELECT ` + attachmentColumns + `
+			FROM attachments a
+			WHERE a.todo_id = $1
+			ORDER BY a.created_at;`
+
+	attachments := make([]models.Attachment, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		return tx.Select(&attachments, SQL, todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+func getAttachment(tx *sqlx.Tx, todoID, attachmentID string) (*models.Attachment, error) {
+	SQL := `SELECT ` + attachmentColumns + `
+			FROM attachments a
+			WHERE a.id = $1
+			AND a.todo_id = $2;`
+
+	var attachment models.Attachment
+	if err := tx.Get(&attachment, SQL, attachmentID, todoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+func GetAttachment(userID, workspaceID, todoID, attachmentID string) (*models.Attachment, error) {
+	var attachment *models.Attachment
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var err error
+		attachment, err = getAttachment(tx, todoID, attachmentID)
+		return err
+	})
+	return attachment, err
+}
+
+// GetSignedAttachment looks up an attachment for a signed download link.
+// The signature is the authorization, so this reads across workspaces.
+func GetSignedAttachment(attachmentID string) (*models.Attachment, error) {
+	SQL := `SELECT ` + attachmentColumns + `
+			FROM attachments a
+			JOIN todos t ON t.id = a.todo_id
+			JOIN lists l ON l.id = t.list_id AND l.archived_at IS NULL
+			WHERE a.id = $1;`
+
+	var attachment models.Attachment
+	err := database.WithoutTenant(func(tx *sqlx.Tx) error {
+		return tx.Get(&attachment, SQL, attachmentID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// DeleteAttachment removes the row and returns its storage key so the
+// caller can delete the blob.
+func DeleteAttachment(userID, workspaceID, todoID, attachmentID string) (string, error) {
+	var storageKey string
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		attachment, err := getAttachment(tx, todoID, attachmentID)
+		if err != nil {
+			return err
+		}
+		storageKey = attachment.StorageKey
+		_, err = tx.Exec(`DELETE FROM attachments WHERE id = $1;`, attachmentID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return storageKey, nil
+}
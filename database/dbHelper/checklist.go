@@ -0,0 +1,186 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var (
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrInvalidChecklistOrder = errors.New("item ids must list every checklist item exactly once")
+)
+
+// checklistProgressJoin expects the todo to be aliased t.
+const checklistProgressJoin = `LEFT JOIN LATERAL (
+				SELECT count(*) AS total,
+				       count(*) FILTER (WHERE complete) AS done
+				FROM checklist_items
+				WHERE todo_id = t.id
+			) c ON TRUE`
+
+const checklistProgressColumns = `c.total AS checklist_total,
+			       c.done AS checklist_done,
+			       c.done::FLOAT / NULLIF(c.total, 0) AS progress`
+
+const checklistColumns = `id, todo_id, name, complete, position, created_at, completed_at`
+
+func GetChecklist(userID, workspaceID, todoID string) ([]models.ChecklistItem, error) {
+	SQL := `SELECT ` + checklistColumns + `
+			FROM checklist_items
+			WHERE todo_id = $1
+			ORDER BY position, created_at;`
+
+	items := make([]models.ChecklistItem, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		return tx.Select(&items, SQL, todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+func CreateChecklistItem(userID, workspaceID, todoID, name string) (*models.ChecklistItem, error) {
+	SQL := `INSERT INTO checklist_items(todo_id, name, position)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(position) + 1, 0) FROM checklist_items WHERE todo_id = $1))
+			RETURNING ` + checklistColumns + `;`
+
+	var item models.ChecklistItem
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		// serialise appends so two items never get the same position
+		if _, err := tx.Exec(`SELECT id FROM todos WHERE id = $1 FOR UPDATE;`, todoID); err != nil {
+			return err
+		}
+		return tx.Get(&item, SQL, todoID, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateChecklistItem renames and/or toggles an item. With autoComplete
+// set, checking off the last open item completes the todo as well.
+func UpdateChecklistItem(userID, workspaceID, todoID, itemID, name string, complete *bool, autoComplete bool) (*models.ChecklistItem, error) {
+	SQL := `UPDATE checklist_items
+			SET name = COALESCE(NULLIF($1, ''), name),
+			    complete = COALESCE($2, complete),
+			    completed_at = CASE
+			        WHEN $2 IS NULL THEN completed_at
+			        WHEN $2 AND NOT complete THEN NOW()
+			        WHEN $2 THEN completed_at
+			    END
+			WHERE id = $3
+			AND todo_id = $4
+			RETURNING ` + checklistColumns + `;`
+
+	var item models.ChecklistItem
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		if err := tx.Get(&item, SQL, name, complete, itemID, todoID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrChecklistItemNotFound
+			}
+			return err
+		}
+		if !autoComplete || complete == nil || !*complete {
+			return nil
+		}
+		result, err := tx.Exec(`UPDATE todos
+				SET complete = TRUE
+				WHERE id = $1
+				AND NOT complete
+				AND NOT EXISTS (SELECT 1 FROM checklist_items WHERE todo_id = $1 AND NOT complete)
+				AND NOT `+openBlockersCondition+`;`, todoID)
+		if err != nil {
+			return err
+		}
+		completed, err := result.RowsAffected()
+		if err != nil || completed == 0 {
+			return err
+		}
+		return spawnNextOccurrence(tx, userID, todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+func DeleteChecklistItem(userID, workspaceID, todoID, itemID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`DELETE FROM checklist_items WHERE id = $1 AND todo_id = $2;`, itemID, todoID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrChecklistItemNotFound
+		}
+		return nil
+	})
+}
+
+// ReorderChecklist sets positions from the order of itemIDs, which must be
+// a permutation of the todo's current items.
+func ReorderChecklist(userID, workspaceID, todoID string, itemIDs []string) ([]models.ChecklistItem, error) {
+	items := make([]models.ChecklistItem, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var current []string
+		if err := tx.Select(&current, `SELECT id FROM checklist_items WHERE todo_id = $1 FOR UPDATE;`, todoID); err != nil {
+			return err
+		}
+		if !samePermutation(current, itemIDs) {
+			return ErrInvalidChecklistOrder
+		}
+		for position, itemID := range itemIDs {
+			if _, err := tx.Exec(`UPDATE checklist_items SET position = $1 WHERE id = $2;`, position, itemID); err != nil {
+				return err
+			}
+		}
+		return tx.Select(&items, `SELECT `+checklistColumns+`
+				FROM checklist_items
+				WHERE todo_id = $1
+				ORDER BY position;`, todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func samePermutation(current, ordered []string) bool {
+	if len(current) != len(ordered) {
+		return false
+	}
+	seen := make(map[string]bool, len(current))
+	for _, id := range current {
+		seen[id] = true
+	}
+	for _, id := range ordered {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
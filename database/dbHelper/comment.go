@@ -0,0 +1,219 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentForbidden = errors.New("only the author can change this comment")
+)
+
+const commentColumns = `c.id, c.todo_id, c.user_id, u.name AS author_name, c.body, c.created_at, c.edited_at`
+
+// saveMentions replaces the mentions of a comment. Only users who can see
+// the todo are resolved; other addresses are left as plain text.
+func saveMentions(tx *sqlx.Tx, commentID, todoID, body string) error {
+	previous := make([]string, 0)
+	if err := tx.Select(&previous, `DELETE FROM comment_mentions WHERE comment_id = $1 RETURNING user_id;`, commentID); err != nil {
+		return err
+	}
+	emails := utils.ParseMentions(body)
+	if len(emails) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(`INSERT INTO comment_mentions(comment_id, user_id)
+			SELECT $1, m.user_id
+			FROM todos t
+			JOIN list_members m ON m.list_id = t.list_id
+			JOIN users u ON u.id = m.user_id
+			WHERE t.id = $2
+			AND u.email = ANY($3)
+			AND u.archived_at IS NULL
+			ON CONFLICT DO NOTHING;`, commentID, todoID, pq.Array(emails))
+	if err != nil {
+		return err
+	}
+
+	// editing a comment only notifies the people it newly mentions
+	_, err = tx.Exec(`INSERT INTO notifications(user_id, actor_id, kind, title, body, todo_id)
+			SELECT cm.user_id, c.user_id, $3, 'Mentioned on ' || t.name, a.name || ': ' || LEFT(c.body, 200), t.id
+			FROM comment_mentions cm
+			JOIN comments c ON c.id = cm.comment_id
+			JOIN todos t ON t.id = c.todo_id
+			JOIN users a ON a.id = c.user_id
+			WHERE cm.comment_id = $1
+			AND cm.user_id <> c.user_id
+			AND NOT cm.user_id = ANY($2);`, commentID, pq.Array(previous), models.NotificationMention)
+	return err
+}
+
+// notifyComment tells the creator and assignee of a todo about a new
+// comment, unless they wrote it or were already notified of a mention.
+func notifyComment(tx *sqlx.Tx, commentID string) error {
+	_, err := tx.Exec(`INSERT INTO notifications(user_id, actor_id, kind, title, body, todo_id)
+			SELECT DISTINCT r.user_id, c.user_id, $2, 'New comment on ' || t.name, a.name || ': ' || LEFT(c.body, 200), t.id
+			FROM comments c
+			JOIN todos t ON t.id = c.todo_id
+			JOIN users a ON a.id = c.user_id
+			CROSS JOIN LATERAL (VALUES (t.user_id), (t.assignee_id)) r(user_id)
+			JOIN list_members m ON m.list_id = t.list_id AND m.user_id = r.user_id
+			WHERE c.id = $1
+			AND r.user_id <> c.user_id
+			AND NOT EXISTS (
+				SELECT 1 FROM comment_mentions cm WHERE cm.comment_id = c.id AND cm.user_id = r.user_id
+			);`, commentID, models.NotificationComment)
+	return err
+}
+func loadMentions(q sqlx.Queryer, comments []models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]string, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].ID
+		comments[i].Mentions = make([]models.CommentMention, 0)
+	}
+	var mentions []models.CommentMention
+	if err := sqlx.Select(q, &mentions, `SELECT cm.comment_id, cm.user_id, u.name, u.email
+			FROM comment_mentions cm
+			JOIN users u ON u.id = cm.user_id
+			WHERE cm.comment_id = ANY($1)
+			ORDER BY u.name;`, pq.Array(ids)); err != nil {
+		return err
+	}
+	for _, mention := range mentions {
+		for i := range comments {
+			if comments[i].ID == mention.CommentID {
+				comments[i].Mentions = append(comments[i].Mentions, mention)
+			}
+		}
+	}
+	return nil
+}
+func getComment(tx *sqlx.Tx, todoID, commentID string) (*models.Comment, error) {
+	SQL := `SELECT ` + commentColumns + `
+			FROM comments c
+			JOIN users u ON u.id = c.user_id
+			WHERE c.id = $1
+			AND c.todo_id = $2
+			AND c.archived_at IS NULL;`
+
+	comments := make([]models.Comment, 1)
+	if err := tx.Get(&comments[0], SQL, commentID, todoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	if err := loadMentions(tx, comments); err != nil {
+		return nil, err
+	}
+	return &comments[0], nil
+}
+
+// Anyone who can see a todo may comment on it, viewers included.
+func CreateComment(userID, workspaceID, todoID, body string) (*models.Comment, error) {
+	var comment *models.Comment
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var commentID string
+		if err := tx.Get(&commentID, `INSERT INTO comments(todo_id, user_id, body)
+				VALUES ($1, $2, $3)
+				RETURNING id;`, todoID, userID, body); err != nil {
+			return err
+		}
+		if err := saveMentions(tx, commentID, todoID, body); err != nil {
+			return err
+		}
+		if err := notifyComment(tx, commentID); err != nil {
+			return err
+		}
+		var err error
+		comment, err = getComment(tx, todoID, commentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+func GetComments(userID, workspaceID, todoID string, limit, offset int) ([]models.Comment, error) {
+	SQL := `SELECT ` + commentColumns + `
+			FROM comments c
+			JOIN users u ON u.id = c.user_id
+			WHERE c.todo_id = $1
+			AND c.archived_at IS NULL
+			ORDER BY c.created_at, c.id
+			LIMIT $2 OFFSET $3;`
+
+	comments := make([]models.Comment, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		if err := tx.Select(&comments, SQL, todoID, limit, offset); err != nil {
+			return err
+		}
+		return loadMentions(tx, comments)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+func UpdateComment(userID, workspaceID, todoID, commentID, body string) (*models.Comment, error) {
+	var comment *models.Comment
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		existing, err := getComment(tx, todoID, commentID)
+		if err != nil {
+			return err
+		}
+		if existing.UserID != userID {
+			return ErrCommentForbidden
+		}
+		if _, err := tx.Exec(`UPDATE comments
+				SET body = $1,
+				    edited_at = NOW()
+				WHERE id = $2;`, body, commentID); err != nil {
+			return err
+		}
+		if err := saveMentions(tx, commentID, todoID, body); err != nil {
+			return err
+		}
+		comment, err = getComment(tx, todoID, commentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+func DeleteComment(userID, workspaceID, todoID, commentID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		existing, err := getComment(tx, todoID, commentID)
+		if err != nil {
+			return err
+		}
+		if existing.UserID != userID {
+			return ErrCommentForbidden
+		}
+		_, err = tx.Exec(`UPDATE comments SET archived_at = NOW() WHERE id = $1;`, commentID)
+		return err
+	})
+}
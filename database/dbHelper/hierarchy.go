@@ -0,0 +1,266 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var (
+	ErrInvalidParent      = errors.New("parent must be another todo in the same list")
+	ErrTodoCycle          = errors.New("change would create a cycle")
+	ErrTodoBlocked        = errors.New("todo has open blockers")
+	ErrDependencyNotFound = errors.New("dependency not found")
+)
+
+const maxSubtreeDepth = 100
+
+// openBlockersCondition expects the todo id as $1.
+const openBlockersCondition = `EXISTS (
+				SELECT 1
+				FROM todo_dependencies d
+				JOIN todos b ON b.id = d.blocked_by_id
+				WHERE d.todo_id = $1
+				AND NOT b.complete
+			)`
+
+// lockTodoGraph serialises parent and dependency changes in a workspace so
+// two concurrent edits cannot close a cycle between them.
+func lockTodoGraph(tx *sqlx.Tx, workspaceID string) error {
+	_, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('todo-graph:' || $1));`, workspaceID)
+	return err
+}
+
+// requireParent checks that parentID can become the parent of todoID, or
+// of a todo that is about to be created when todoID is empty.
+func requireParent(tx *sqlx.Tx, listID, todoID, parentID string) error {
+	var parentListID string
+	if err := tx.Get(&parentListID, `SELECT list_id FROM todos WHERE id = $1;`, parentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidParent
+		}
+		return err
+	}
+	if parentListID != listID || parentID == todoID {
+		return ErrInvalidParent
+	}
+	if todoID == "" {
+		return nil
+	}
+
+	var cycle bool
+	err := tx.Get(&cycle, `WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM todos WHERE id = $1
+				UNION
+				SELECT t.id, t.parent_id
+				FROM todos t
+				JOIN ancestors a ON t.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2);`, parentID, todoID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return ErrTodoCycle
+	}
+	return nil
+}
+
+// SetTodoParent moves a todo under parentID, or to the top level when
+// parentID is nil.
+func SetTodoParent(userID, workspaceID, todoID string, parentID *string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		if err := lockTodoGraph(tx, workspaceID); err != nil {
+			return err
+		}
+		if parentID != nil {
+			var listID string
+			if err := tx.Get(&listID, `SELECT list_id FROM todos WHERE id = $1;`, todoID); err != nil {
+				return err
+			}
+			if err := requireParent(tx, listID, todoID, *parentID); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(`UPDATE todos SET parent_id = $1 WHERE id = $2;`, parentID, todoID)
+		return err
+	})
+}
+
+// GetTodoSubtree returns todoID with all of its descendants nested below it.
+func GetTodoSubtree(userID, workspaceID, todoID string) (*models.TodoNode, error) {
+	SQL := `WITH RECURSIVE subtree AS (
+				SELECT id, 0 AS depth FROM todos WHERE id = $1
+				UNION ALL
+				SELECT t.id, s.depth + 1
+				FROM todos t
+				JOIN subtree s ON t.parent_id = s.id
+				WHERE s.depth < $3
+			)
+			SELECT ` + todoColumns + `,
+			       ` + checklistProgressColumns + `
+			FROM subtree s
+			JOIN todos t ON t.id = s.id
+			JOIN list_members m ON m.list_id = t.list_id AND m.user_id = $2
+			JOIN lists l ON l.id = t.list_id AND l.archived_at IS NULL
+			` + checklistProgressJoin + `
+			ORDER BY s.depth, t.position, t.id;`
+
+	var todos []models.Todos
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		return tx.Select(&todos, SQL, todoID, userID, maxSubtreeDepth)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// rows come parents first, so every child finds its parent already built
+	nodes := make(map[string]*models.TodoNode, len(todos))
+	var root *models.TodoNode
+	for _, todo := range todos {
+		node := &models.TodoNode{Todos: todo, Children: make([]*models.TodoNode, 0)}
+		nodes[todo.Id] = node
+		if todo.Id == todoID {
+			root = node
+			continue
+		}
+		if todo.ParentId != nil {
+			if parent, ok := nodes[*todo.ParentId]; ok {
+				parent.Children = append(parent.Children, node)
+			}
+		}
+	}
+	if root == nil {
+		return nil, ErrTodoNotFound
+	}
+	return root, nil
+}
+func AddTodoDependency(userID, workspaceID, todoID, blockedByID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		if _, err := getTodoRole(tx, blockedByID, userID, workspaceID); err != nil {
+			return err
+		}
+		if err := lockTodoGraph(tx, workspaceID); err != nil {
+			return err
+		}
+
+		// the new edge closes a cycle if the blocker already waits on todoID
+		var cycle bool
+		err := tx.Get(&cycle, `WITH RECURSIVE blockers AS (
+					SELECT blocked_by_id AS id FROM todo_dependencies WHERE todo_id = $1
+					UNION
+					SELECT d.blocked_by_id
+					FROM todo_dependencies d
+					JOIN blockers b ON d.todo_id = b.id
+				)
+				SELECT $1::UUID = $2::UUID OR EXISTS (SELECT 1 FROM blockers WHERE id = $2);`, blockedByID, todoID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrTodoCycle
+		}
+		_, err = tx.Exec(`INSERT INTO todo_dependencies(todo_id, blocked_by_id, created_by)
+				VALUES ($1, $2, $3)
+				ON CONFLICT DO NOTHING;`, todoID, blockedByID, userID)
+		return err
+	})
+}
+func RemoveTodoDependency(userID, workspaceID, todoID, blockedByID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`DELETE FROM todo_dependencies
+				WHERE todo_id = $1
+				AND blocked_by_id = $2;`, todoID, blockedByID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrDependencyNotFound
+		}
+		return nil
+	})
+}
+
+// GetDependencyGraph returns everything todoID transitively waits on and
+// everything that transitively waits on it. Todos the user cannot see are
+// left out together with their edges.
+func GetDependencyGraph(userID, workspaceID, todoID string) (*models.DependencyGraph, error) {
+	edgesSQL := `WITH RECURSIVE upstream AS (
+				SELECT todo_id, blocked_by_id FROM todo_dependencies WHERE todo_id = $1
+				UNION
+				SELECT d.todo_id, d.blocked_by_id
+				FROM todo_dependencies d
+				JOIN upstream u ON d.todo_id = u.blocked_by_id
+			), downstream AS (
+				SELECT todo_id, blocked_by_id FROM todo_dependencies WHERE blocked_by_id = $1
+				UNION
+				SELECT d.todo_id, d.blocked_by_id
+				FROM todo_dependencies d
+				JOIN downstream w ON d.blocked_by_id = w.todo_id
+			)
+			SELECT todo_id, blocked_by_id FROM upstream
+			UNION
+			SELECT todo_id, blocked_by_id FROM downstream;`
+
+	nodesSQL := `SELECT t.id, t.list_id, t.name, t.complete
+			FROM todos t
+			JOIN list_members m ON m.list_id = t.list_id AND m.user_id = $2
+			JOIN lists l ON l.id = t.list_id AND l.archived_at IS NULL
+			WHERE t.id = ANY($1)
+			ORDER BY t.created_at;`
+
+	graph := &models.DependencyGraph{
+		Nodes: make([]models.DependencyNode, 0),
+		Edges: make([]models.DependencyEdge, 0),
+	}
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var edges []models.DependencyEdge
+		if err := tx.Select(&edges, edgesSQL, todoID); err != nil {
+			return err
+		}
+		ids := []string{todoID}
+		for _, edge := range edges {
+			ids = append(ids, edge.TodoID, edge.BlockedByID)
+		}
+		if err := tx.Select(&graph.Nodes, nodesSQL, pq.Array(ids), userID); err != nil {
+			return err
+		}
+		visible := make(map[string]bool, len(graph.Nodes))
+		for _, node := range graph.Nodes {
+			visible[node.ID] = true
+		}
+		for _, edge := range edges {
+			if visible[edge.TodoID] && visible[edge.BlockedByID] {
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
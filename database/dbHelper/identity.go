@@ -0,0 +1,132 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var (
+	ErrInvalidOIDCState = errors.New("invalid or expired login state")
+	ErrAccountDisabled  = errors.New("account disabled")
+)
+
+func CreateOIDCLoginState(provider, state, nonce, codeVerifier string, ttl time.Duration) error {
+	SQL := `INSERT INTO oidc_login_states(state_hash, provider, nonce, code_verifier, expires_at)
+			VALUES ($1, $2, $3, $4, $5);`
+	_, err := database.Todo.Exec(SQL, utils.HashToken(state), provider, nonce, codeVerifier, time.Now().Add(ttl))
+	return err
+}
+func ConsumeOIDCLoginState(provider, state string) (*models.OIDCLoginState, error) {
+	SQL := `UPDATE oidc_login_states
+			SET used_at = NOW()
+			WHERE state_hash = $1
+			AND provider = $2
+			AND used_at IS NULL
+			AND expires_at > NOW()
+			RETURNING nonce, code_verifier;`
+
+	var loginState models.OIDCLoginState
+	err := database.Todo.Get(&loginState, SQL, utils.HashToken(state), provider)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+	return &loginState, nil
+}
+
+// ResolveIdentity maps an external identity to a user, linking it to an
+// existing account only when the provider vouches for the email address and
+// the account has verified it too.
+func ResolveIdentity(provider string, identity models.ExternalIdentity) (string, error) {
+	var userID string
+	err := database.Tx(func(tx *sqlx.Tx) error {
+		var linked struct {
+			UserID   string `db:"user_id"`
+			Archived bool   `db:"archived"`
+		}
+		err := tx.Get(&linked, `SELECT i.user_id, u.archived_at IS NOT NULL AS archived
+				FROM identities i
+				JOIN users u ON u.id = i.user_id
+				WHERE i.provider = $1
+				AND i.subject = $2;`, provider, identity.Subject)
+		if err == nil {
+			if linked.Archived {
+				return ErrAccountDisabled
+			}
+			userID = linked.UserID
+			_, err = tx.Exec(`UPDATE identities
+					SET last_login_at = NOW(),
+					    email = $3
+					WHERE provider = $1
+					AND subject = $2;`, provider, identity.Subject, identity.Email)
+			return err
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		// only accounts that proved they own the address are linked; an
+		// unverified one may have been registered by someone else to hijack
+		// the later provider login, so it falls through to ErrEmailTaken below
+		if identity.Email != "" && identity.EmailVerified {
+			err = tx.Get(&userID, `SELECT id
+					FROM users
+					WHERE email = TRIM(LOWER($1))
+					AND archived_at IS NULL
+					AND verified_at IS NOT NULL;`, identity.Email)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		}
+
+		if userID == "" {
+			if identity.Email == "" {
+				return errors.New("identity provider did not return an email address")
+			}
+			// external accounts get an unguessable password until they set one via reset
+			randomPassword, err := utils.GenerateRandomToken()
+			if err != nil {
+				return err
+			}
+			hashPassword, err := utils.HashPassword(randomPassword)
+			if err != nil {
+				return err
+			}
+			name := identity.Name
+			if name == "" {
+				name = identity.Email
+			}
+			var verifiedAt *time.Time
+			if identity.EmailVerified {
+				now := time.Now()
+				verifiedAt = &now
+			}
+			if err := tx.Get(&userID, `INSERT INTO users(name, email, password, verified_at)
+					VALUES ($1, TRIM(LOWER($2)), $3, $4) RETURNING id;`,
+				name, identity.Email, hashPassword, verifiedAt); err != nil {
+				var pqErr *pq.Error
+				if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "unique_user" {
+					return ErrEmailTaken
+				}
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`INSERT INTO identities(user_id, provider, subject, email)
+				VALUES ($1, $2, $3, $4);`, userID, provider, identity.Subject, identity.Email)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return userID, nil
+}
@@ -0,0 +1,394 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var (
+	ErrListNotFound          = errors.New("list not found")
+	ErrListForbidden         = errors.New("insufficient list permissions")
+	ErrInvalidInvitation     = errors.New("invalid or expired invitation")
+	ErrInvitationEmail       = errors.New("invitation was sent to a different email address")
+	ErrPersonalListProtected = errors.New("personal list cannot be deleted")
+	ErrOwnerMembership       = errors.New("the list owner cannot be changed or removed")
+)
+
+const listColumns = `l.id, l.workspace_id, l.owner_id, l.name, l.is_personal, m.role, l.created_at`
+
+func CanWrite(role string) bool {
+	return role == models.ListRoleOwner || role == models.ListRoleEditor
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// getListRole returns ErrListNotFound when the user is not a member, so
+// lists the user cannot see are indistinguishable from missing ones.
+func getListRole(q sqlx.Queryer, userID, workspaceID, listID string) (string, error) {
+	SQL := `SELECT m.role
+			FROM list_members m
+			JOIN lists l ON l.id = m.list_id
+			WHERE m.list_id = $1
+			AND m.user_id = $2
+			AND l.workspace_id = $3
+			AND l.archived_at IS NULL;`
+
+	var role string
+	err := sqlx.Get(q, &role, SQL, listID, userID, workspaceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrListNotFound
+		}
+		return "", err
+	}
+	return role, nil
+}
+func requireListWrite(q sqlx.Queryer, userID, workspaceID, listID string) error {
+	role, err := getListRole(q, userID, workspaceID, listID)
+	if err != nil {
+		return err
+	}
+	if !CanWrite(role) {
+		return ErrListForbidden
+	}
+	return nil
+}
+func requireListOwner(q sqlx.Queryer, userID, workspaceID, listID string) error {
+	role, err := getListRole(q, userID, workspaceID, listID)
+	if err != nil {
+		return err
+	}
+	if role != models.ListRoleOwner {
+		return ErrListForbidden
+	}
+	return nil
+}
+func GetListRole(userID, workspaceID, listID string) (string, error) {
+	var role string
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		var err error
+		role, err = getListRole(tx, userID, workspaceID, listID)
+		return err
+	})
+	return role, err
+}
+func GetOrCreatePersonalList(userID, workspaceID string) (string, error) {
+	var listID string
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		err := tx.Get(&listID, `INSERT INTO lists(workspace_id, owner_id, name, is_personal)
+				VALUES ($1, $2, 'Personal', TRUE)
+				ON CONFLICT (workspace_id, owner_id) WHERE is_personal DO NOTHING
+				RETURNING id;`, workspaceID, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return tx.Get(&listID, `SELECT id
+					FROM lists
+					WHERE workspace_id = $1
+					AND owner_id = $2
+					AND is_personal;`, workspaceID, userID)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO list_members(list_id, user_id, role)
+				VALUES ($1, $2, 'owner');`, listID, userID)
+		return err
+	})
+	return listID, err
+}
+func CreateList(userID, workspaceID, name string) (*models.List, error) {
+	list := &models.List{}
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := tx.Get(list, `INSERT INTO lists(workspace_id, owner_id, name)
+				VALUES ($1, $2, $3)
+				RETURNING id, workspace_id, owner_id, name, is_personal, 'owner' AS role, created_at;`,
+			workspaceID, userID, name); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO list_members(list_id, user_id, role)
+				VALUES ($1, $2, 'owner');`, list.ID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+func GetLists(userID, workspaceID string) ([]models.List, error) {
+	SQL := `SELECT ` + listColumns + `
+			FROM lists l
+			JOIN list_members m ON m.list_id = l.id
+			WHERE m.user_id = $1
+			AND l.workspace_id = $2
+			AND l.archived_at IS NULL
+			ORDER BY l.is_personal DESC, l.created_at;`
+
+	lists := make([]models.List, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		return tx.Select(&lists, SQL, userID, workspaceID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+func getListByID(q sqlx.Queryer, userID, workspaceID, listID string) (*models.List, error) {
+	SQL := `SELECT ` + listColumns + `
+			FROM lists l
+			JOIN list_members m ON m.list_id = l.id
+			WHERE l.id = $1
+			AND m.user_id = $2
+			AND l.workspace_id = $3
+			AND l.archived_at IS NULL;`
+
+	var list models.List
+	err := sqlx.Get(q, &list, SQL, listID, userID, workspaceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+func GetListByID(userID, workspaceID, listID string) (*models.List, error) {
+	var list *models.List
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		var err error
+		list, err = getListByID(tx, userID, workspaceID, listID)
+		return err
+	})
+	return list, err
+}
+func RenameList(userID, workspaceID, listID, name string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireListOwner(tx, userID, workspaceID, listID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE lists SET name = $1 WHERE id = $2 AND workspace_id = $3;`, name, listID, workspaceID)
+		return err
+	})
+}
+func ArchiveList(userID, workspaceID, listID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		list, err := getListByID(tx, userID, workspaceID, listID)
+		if err != nil {
+			return err
+		}
+		if list.Role != models.ListRoleOwner {
+			return ErrListForbidden
+		}
+		if list.IsPersonal {
+			return ErrPersonalListProtected
+		}
+		_, err = tx.Exec(`UPDATE lists SET archived_at = NOW() WHERE id = $1 AND workspace_id = $2;`, listID, workspaceID)
+		return err
+	})
+}
+func GetListMembers(userID, workspaceID, listID string) ([]models.ListMember, error) {
+	SQL := `SELECT m.user_id, u.name, u.email, m.role, m.created_at
+			FROM list_members m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.list_id = $1
+			ORDER BY m.created_at;`
+
+	members := make([]models.ListMember, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getListRole(tx, userID, workspaceID, listID); err != nil {
+			return err
+		}
+		return tx.Select(&members, SQL, listID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+func UpdateListMember(userID, workspaceID, listID, memberID, role string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireListOwner(tx, userID, workspaceID, listID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`UPDATE list_members
+				SET role = $1
+				WHERE list_id = $2
+				AND user_id = $3
+				AND role <> 'owner';`, role, listID, memberID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			if memberID == userID {
+				return ErrOwnerMembership
+			}
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// RemoveListMember lets owners remove anyone but themselves and lets any
+// other member leave the list.
+func RemoveListMember(userID, workspaceID, listID, memberID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		callerRole, err := getListRole(tx, userID, workspaceID, listID)
+		if err != nil {
+			return err
+		}
+		if memberID == userID && callerRole == models.ListRoleOwner {
+			return ErrOwnerMembership
+		}
+		if memberID != userID && callerRole != models.ListRoleOwner {
+			return ErrListForbidden
+		}
+		result, err := tx.Exec(`DELETE FROM list_members
+				WHERE list_id = $1
+				AND user_id = $2
+				AND role <> 'owner';`, listID, memberID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+		return unassignMember(tx, userID, workspaceID, listID, memberID)
+	})
+}
+
+// CreateListInvitation returns the id of the new invitation and its token.
+func CreateListInvitation(userID, workspaceID, listID, email, role string, ttl time.Duration) (string, string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	SQL := `INSERT INTO list_invitations(list_id, email, role, token_hash, invited_by, expires_at)
+			VALUES ($1, TRIM(LOWER($2)), $3, $4, $5, $6)
+			RETURNING id;`
+
+	var invitationID string
+	err = database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireListOwner(tx, userID, workspaceID, listID); err != nil {
+			return err
+		}
+		return tx.Get(&invitationID, SQL, listID, email, role, utils.HashToken(token), userID, time.Now().Add(ttl))
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return invitationID, token, nil
+}
+func GetListInvitations(userID, workspaceID, listID string) ([]models.ListInvitation, error) {
+	SQL := `SELECT id, list_id, email, role, invited_by, expires_at, accepted_at, created_at
+			FROM list_invitations
+			WHERE list_id = $1
+			AND revoked_at IS NULL
+			ORDER BY created_at DESC;`
+
+	invitations := make([]models.ListInvitation, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireListOwner(tx, userID, workspaceID, listID); err != nil {
+			return err
+		}
+		return tx.Select(&invitations, SQL, listID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+func RevokeListInvitation(userID, workspaceID, listID, invitationID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireListOwner(tx, userID, workspaceID, listID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`UPDATE list_invitations
+				SET revoked_at = NOW()
+				WHERE id = $1
+				AND list_id = $2
+				AND accepted_at IS NULL
+				AND revoked_at IS NULL;`, invitationID, listID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// AcceptListInvitation adds the user to the list and, if needed, to the
+// workspace holding it. It returns the list and workspace ids.
+func AcceptListInvitation(userID, token string) (string, string, error) {
+	var invitation struct {
+		ID          string `db:"id"`
+		ListID      string `db:"list_id"`
+		WorkspaceID string `db:"workspace_id"`
+		Email       string `db:"email"`
+		Role        string `db:"role"`
+	}
+	err := database.WithoutTenant(func(tx *sqlx.Tx) error {
+		if err := tx.Get(&invitation, `SELECT i.id, i.list_id, l.workspace_id, i.email, i.role
+				FROM list_invitations i
+				JOIN lists l ON l.id = i.list_id
+				WHERE i.token_hash = $1
+				AND i.accepted_at IS NULL
+				AND i.revoked_at IS NULL
+				AND i.expires_at > NOW()
+				AND l.archived_at IS NULL
+				FOR UPDATE OF i;`, utils.HashToken(token)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidInvitation
+			}
+			return err
+		}
+
+		var email string
+		if err := tx.Get(&email, `SELECT email FROM users WHERE id = $1;`, userID); err != nil {
+			return err
+		}
+		if email != invitation.Email {
+			return ErrInvitationEmail
+		}
+
+		if _, err := tx.Exec(`UPDATE list_invitations SET accepted_at = NOW() WHERE id = $1;`, invitation.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO workspace_members(workspace_id, user_id, role)
+				VALUES ($1, $2, 'member')
+				ON CONFLICT (workspace_id, user_id) DO NOTHING;`, invitation.WorkspaceID, userID); err != nil {
+			return err
+		}
+		// an existing membership keeps its role; owners are never downgraded
+		_, err := tx.Exec(`INSERT INTO list_members(list_id, user_id, role)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (list_id, user_id) DO NOTHING;`, invitation.ListID, userID, invitation.Role)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return invitation.ListID, invitation.WorkspaceID, nil
+}
@@ -0,0 +1,135 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+// failures older than this no longer count towards a lockout
+const loginFailureWindow = 24 * time.Hour
+
+func EmailThrottleKey(email string) string {
+	return "email:" + strings.TrimSpace(strings.ToLower(email))
+}
+func IPThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+func TwoFactorThrottleKey(userID string) string {
+	return "2fa:" + userID
+}
+
+// verification resends are counted and locked out like failed logins
+func ResendEmailThrottleKey(email string) string {
+	return "resend-email:" + strings.TrimSpace(strings.ToLower(email))
+}
+func ResendIPThrottleKey(ip string) string {
+	return "resend-ip:" + ip
+}
+
+func loginThreshold(key string) int {
+	if strings.HasPrefix(key, "ip:") {
+		return utils.GetEnvInt("LOGIN_MAX_FAILURES_PER_IP", utils.DefaultLoginMaxFailuresPerIP)
+	}
+	if strings.HasPrefix(key, "2fa:") {
+		return utils.GetEnvInt("LOGIN_MAX_TWO_FACTOR_FAILURES", utils.DefaultLoginMaxTwoFactorFailures)
+	}
+	if strings.HasPrefix(key, "resend-email:") {
+		return utils.GetEnvInt("VERIFICATION_MAX_RESENDS_PER_EMAIL", utils.DefaultVerificationMaxResendsPerEmail)
+	}
+	if strings.HasPrefix(key, "resend-ip:") {
+		return utils.GetEnvInt("VERIFICATION_MAX_RESENDS_PER_IP", utils.DefaultVerificationMaxResendsPerIP)
+	}
+	return utils.GetEnvInt("LOGIN_MAX_FAILURES_PER_EMAIL", utils.DefaultLoginMaxFailuresPerEmail)
+}
+
+// lockoutDuration backs off exponentially (1s, 2s, 4s, ...) below the
+// threshold and locks the key out once the threshold is reached. The back
+// off never exceeds the lockout, however high the threshold is set.
+func lockoutDuration(key string, failures int) time.Duration {
+	threshold := loginThreshold(key)
+	lockout := utils.GetEnvDuration("LOGIN_LOCKOUT_DURATION", utils.DefaultLoginLockoutDuration)
+	if failures >= threshold {
+		return lockout
+	}
+	if failures < 2 {
+		return 0
+	}
+	return min(time.Second<<min(failures-2, 16), lockout)
+}
+
+func GetLoginLockedUntil(keys ...string) (*time.Time, error) {
+	SQL := `SELECT MAX(locked_until)
+			FROM login_attempts
+			WHERE key = ANY($1)
+			AND locked_until > NOW();`
+
+	var lockedUntil *time.Time
+	err := database.Todo.Get(&lockedUntil, SQL, pq.Array(keys))
+	if err != nil {
+		return nil, err
+	}
+	return lockedUntil, nil
+}
+func RecordLoginFailure(key string) error {
+	SQL := `INSERT INTO login_attempts(key, failures, last_failure_at)
+			VALUES ($1, 1, NOW())
+			ON CONFLICT (key) DO UPDATE
+			SET failures = CASE
+			        WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $2) THEN 1
+			        ELSE login_attempts.failures + 1
+			    END,
+			    last_failure_at = NOW()
+			RETURNING failures;`
+
+	var failures int
+	if err := database.Todo.Get(&failures, SQL, key, loginFailureWindow.Seconds()); err != nil {
+		return err
+	}
+
+	delay := lockoutDuration(key, failures)
+	if delay == 0 {
+		return nil
+	}
+	_, err := database.Todo.Exec(`UPDATE login_attempts SET locked_until = $2 WHERE key = $1;`,
+		key, time.Now().Add(delay))
+	return err
+}
+func ClearLoginFailures(key string) error {
+	_, err := database.Todo.Exec(`DELETE FROM login_attempts WHERE key = $1;`, key)
+	return err
+}
+func GetLoginThrottle(key string) (*models.LoginThrottle, error) {
+	SQL := `SELECT key, failures, last_failure_at, locked_until
+			FROM login_attempts
+			WHERE key = $1;`
+
+	var throttle models.LoginThrottle
+	err := database.Todo.Get(&throttle, SQL, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.LoginThrottle{Key: key}, nil
+		}
+		return nil, err
+	}
+	return &throttle, nil
+}
+func GetActiveLockouts() ([]models.LoginThrottle, error) {
+	SQL := `SELECT key, failures, last_failure_at, locked_until
+			FROM login_attempts
+			WHERE locked_until > NOW()
+			ORDER BY locked_until DESC;`
+
+	throttles := make([]models.LoginThrottle, 0)
+	err := database.Todo.Select(&throttles, SQL)
+	if err != nil {
+		return nil, err
+	}
+	return throttles, nil
+}
@@ -0,0 +1,115 @@
+package dbHelper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+func CreateNotification(userID, kind, title, body string, todoID *string) error {
+	SQL := `INSERT INTO notifications(user_id, kind, title, body, todo_id)
+			VALUES ($1, $2, $3, $4, $5);`
+
+	_, err := database.Todo.Exec(SQL, userID, kind, title, body, todoID)
+	return err
+}
+
+// GetNotifications returns the newest notifications first, starting below
+// cursor when one is given.
+func GetNotifications(userID string, unreadOnly bool, cursor string, limit int) (*models.NotificationPage, error) {
+	SQL := `SELECT id, user_id, actor_id, kind, title, body, todo_id, read_at, created_at
+			FROM notifications
+			WHERE user_id = $1
+			AND (NOT $2 OR read_at IS NULL)
+			AND ($3::TIMESTAMPTZ IS NULL OR (created_at, id) < ($3, $4::UUID))
+			ORDER BY created_at DESC, id DESC
+			LIMIT $5;`
+
+	var (
+		after   *time.Time
+		afterID *string
+	)
+	if cursor != "" {
+		createdAt, id, err := utils.DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after, afterID = &createdAt, &id
+	}
+
+	page := &models.NotificationPage{Notifications: make([]models.Notification, 0, limit+1)}
+	// one extra row tells whether another page follows
+	if err := database.Todo.Select(&page.Notifications, SQL, userID, unreadOnly, after, afterID, limit+1); err != nil {
+		return nil, err
+	}
+	if len(page.Notifications) > limit {
+		page.Notifications = page.Notifications[:limit]
+		last := page.Notifications[limit-1]
+		page.NextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
+func GetUnreadNotificationCount(userID string) (int, error) {
+	SQL := `SELECT count(*)
+			FROM notifications
+			WHERE user_id = $1
+			AND read_at IS NULL;`
+
+	var count int
+	err := database.Todo.Get(&count, SQL, userID)
+	return count, err
+}
+func MarkNotificationRead(userID, notificationID string) error {
+	SQL := `UPDATE notifications
+			SET read_at = COALESCE(read_at, NOW())
+			WHERE id = $1
+			AND user_id = $2;`
+
+	result, err := database.Todo.Exec(SQL, notificationID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+func MarkAllNotificationsRead(userID string) (int64, error) {
+	SQL := `UPDATE notifications
+			SET read_at = NOW()
+			WHERE user_id = $1
+			AND read_at IS NULL;`
+
+	result, err := database.Todo.Exec(SQL, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+func DeleteNotification(userID, notificationID string) error {
+	SQL := `DELETE FROM notifications
+			WHERE id = $1
+			AND user_id = $2;`
+
+	result, err := database.Todo.Exec(SQL, notificationID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var (
+	ErrInvalidSort     = errors.New("invalid sort field")
+	ErrInvalidPosition = errors.New("todo can only be placed after another todo in the same list")
+)
+
+const positionGap = 1024
+
+var todoSortColumns = map[string]string{
+	"priority":    `CASE t.priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END`,
+	"expiring_at": "t.expiring_at",
+	"created_at":  "t.created_at",
+	"updated_at":  "t.updated_at",
+	"name":        "LOWER(t.name)",
+	"position":    "t.position",
+}
+
+// ParseTodoSort parses a comma separated list of sort fields where a
+// leading "-" sorts that field in descending order.
+func ParseTodoSort(raw string) ([]models.TodoSort, error) {
+	sorts := make([]models.TodoSort, 0)
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		sort := models.TodoSort{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if _, ok := todoSortColumns[sort.Field]; !ok {
+			return nil, ErrInvalidSort
+		}
+		if seen[sort.Field] {
+			continue
+		}
+		seen[sort.Field] = true
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+// todoOrderBy always ends with t.id so rows that tie on every key keep a
+// stable order between requests.
+func todoOrderBy(sorts []models.TodoSort) string {
+	if len(sorts) == 0 {
+		return "t.expiring_at, t.id"
+	}
+	keys := make([]string, 0, len(sorts)+1)
+	for _, sort := range sorts {
+		direction := " ASC NULLS LAST"
+		if sort.Desc {
+			direction = " DESC NULLS LAST"
+		}
+		keys = append(keys, todoSortColumns[sort.Field]+direction)
+	}
+	return strings.Join(append(keys, "t.id"), ", ")
+}
+
+// MoveTodo places todoID right after afterID in its list, or first when
+// afterID is nil.
+func MoveTodo(userID, workspaceID, todoID string, afterID *string) (*models.Todos, error) {
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var listID string
+		if err := tx.Get(&listID, `SELECT list_id FROM todos WHERE id = $1;`, todoID); err != nil {
+			return err
+		}
+		// moves within one list are serialised so neighbours cannot shift underneath us
+		if _, err := tx.Exec(`SELECT id FROM lists WHERE id = $1 FOR UPDATE;`, listID); err != nil {
+			return err
+		}
+		position, err := positionAfter(tx, listID, todoID, afterID, true)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE todos SET position = $2 WHERE id = $1;`, todoID, position)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetTodoByID(todoID, userID, workspaceID)
+}
+
+func positionAfter(tx *sqlx.Tx, listID, todoID string, afterID *string, canRenumber bool) (float64, error) {
+	var lower float64
+	if afterID != nil {
+		if *afterID == todoID {
+			return 0, ErrInvalidPosition
+		}
+		err := tx.Get(&lower, `SELECT position FROM todos WHERE id = $1 AND list_id = $2;`, *afterID, listID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, ErrInvalidPosition
+			}
+			return 0, err
+		}
+	}
+
+	var upper sql.NullFloat64
+	err := tx.Get(&upper, `SELECT MIN(position)
+			FROM todos
+			WHERE list_id = $1
+			AND id <> $2
+			AND position > $3;`, listID, todoID, lower)
+	if err != nil {
+		return 0, err
+	}
+	if !upper.Valid {
+		return lower + positionGap, nil
+	}
+	// once the neighbours are adjacent floats the midpoint rounds onto one
+	// of them, so the list is renumbered first
+	mid := lower + (upper.Float64-lower)/2
+	if (mid > lower && mid < upper.Float64) || !canRenumber {
+		return mid, nil
+	}
+
+	_, err = tx.Exec(`UPDATE todos t
+			SET position = o.rn * $2
+			FROM (
+				SELECT id, row_number() OVER (ORDER BY position, id) AS rn
+				FROM todos
+				WHERE list_id = $1
+			) o
+			WHERE o.id = t.id;`, listID, positionGap)
+	if err != nil {
+		return 0, err
+	}
+	return positionAfter(tx, listID, todoID, afterID, false)
+}
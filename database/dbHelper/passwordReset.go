@@ -0,0 +1,81 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+func GetUserIDByEmail(email string) (string, error) {
+	SQL := `SELECT id
+			FROM users
+			WHERE email = TRIM(LOWER($1))
+			AND archived_at IS NULL;`
+
+	var userID string
+	err := database.Todo.Get(&userID, SQL, email)
+	return userID, err
+}
+func CreatePasswordResetToken(userID string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	SQL := `INSERT INTO password_reset_tokens(user_id, token_hash, expires_at)
+			VALUES ($1, $2, $3);`
+	_, err = database.Todo.Exec(SQL, userID, utils.HashToken(token), time.Now().Add(ttl))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+func ResetPassword(token, hashedPassword string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		SQL := `SELECT user_id
+				FROM password_reset_tokens
+				WHERE token_hash = $1
+				AND used_at IS NULL
+				AND expires_at > NOW()
+				FOR UPDATE;`
+
+		var userID string
+		if err := tx.Get(&userID, SQL, utils.HashToken(token)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		// consuming one token burns every other outstanding token of the user
+		if _, err := tx.Exec(`UPDATE password_reset_tokens
+				SET used_at = NOW()
+				WHERE user_id = $1
+				AND used_at IS NULL;`, userID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE users
+				SET password = $1
+				WHERE id = $2
+				AND archived_at IS NULL;`, hashedPassword, userID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE user_session
+				SET archived_at = NOW()
+				WHERE user_id = $1
+				AND archived_at IS NULL;`, userID); err != nil {
+			return err
+		}
+		// a reset means the account may be compromised, so API keys go too
+		_, err := tx.Exec(`UPDATE api_keys
+				SET revoked_at = NOW()
+				WHERE user_id = $1
+				AND revoked_at IS NULL;`, userID)
+		return err
+	})
+}
@@ -0,0 +1,281 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/recurrence"
+)
+
+var ErrNotRecurring = errors.New("todo is not recurring")
+
+// the timezone is the series owner's current one, so changing it moves
+// future occurrences with the user
+const seriesColumns = `s.id,s.user_id,s.rrule,s.dtstart,s.first_occurrence,s.name,s.description,s.priority,s.created_at,s.updated_at,
+			       u.timezone,t.occurrence`
+
+func getTodoSeries(q sqlx.Queryer, todoID string) (*models.TodoSeries, error) {
+	SQL := `SELECT ` + seriesColumns + `
+			FROM todos t
+			JOIN todo_series s ON s.id = t.series_id
+			JOIN users u ON u.id = s.user_id
+			WHERE t.id = $1;`
+
+	var series models.TodoSeries
+	if err := sqlx.Get(q, &series, SQL, todoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotRecurring
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+func userLocation(q sqlx.Queryer, userID string) (*time.Location, error) {
+	var timezone string
+	if err := sqlx.Get(q, &timezone, `SELECT timezone FROM users WHERE id = $1;`, userID); err != nil {
+		return nil, err
+	}
+	return loadLocation(timezone), nil
+}
+
+// loadLocation falls back to UTC for zones that were valid when stored but
+// are unknown to this build.
+func loadLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func seriesRule(series *models.TodoSeries) (*recurrence.Rule, *time.Location, error) {
+	loc := loadLocation(series.Timezone)
+	rule, err := recurrence.Parse(series.RRule, loc)
+	return rule, loc, err
+}
+
+// nextOccurrence is the occurrence that completing the current one creates.
+// Slots that are already in the past are skipped.
+func nextOccurrence(series *models.TodoSeries) (time.Time, int, bool, error) {
+	rule, loc, err := seriesRule(series)
+	if err != nil {
+		return time.Time{}, 0, false, err
+	}
+	next, index, ok := rule.Next(series.DTStart, series.Occurrence-series.FirstOccurrence, time.Now(), loc)
+	return next, series.FirstOccurrence + index, ok, nil
+}
+
+// spawnNextOccurrence creates the occurrence after todoID once it has been
+// completed. Completing the same occurrence twice creates nothing new.
+func spawnNextOccurrence(tx *sqlx.Tx, userID, todoID string) error {
+	series, err := getTodoSeries(tx, todoID)
+	if err != nil {
+		if errors.Is(err, ErrNotRecurring) {
+			return nil
+		}
+		return err
+	}
+	expiringAt, occurrence, ok, err := nextOccurrence(series)
+	if err != nil || !ok {
+		return err
+	}
+
+	SQL := `INSERT INTO todos (user_id,workspace_id,list_id,assignee_id,parent_id,series_id,occurrence,name,description,priority,expiring_at,position)
+			SELECT t.user_id,t.workspace_id,t.list_id,t.assignee_id,t.parent_id,s.id,$2,s.name,s.description,s.priority,$3,
+			       (SELECT COALESCE(MAX(position), 0) + $4 FROM todos WHERE list_id = t.list_id)
+			FROM todos t
+			JOIN todo_series s ON s.id = t.series_id
+			WHERE t.id = $1
+			AND NOT EXISTS (SELECT 1 FROM todos n WHERE n.series_id = s.id AND n.occurrence > t.occurrence)
+			ON CONFLICT (series_id, occurrence) WHERE series_id IS NOT NULL DO NOTHING
+			RETURNING id,assignee_id;`
+
+	var created struct {
+		ID         string  `db:"id"`
+		AssigneeID *string `db:"assignee_id"`
+	}
+	if err := tx.Get(&created, SQL, todoID, occurrence, expiringAt, positionGap); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	// the new occurrence starts with the same tags, an unchecked checklist
+	// and the reminders that are relative to its due date
+	if _, err := tx.Exec(`INSERT INTO todo_tags (todo_id, tag_id)
+			SELECT $2, tag_id FROM todo_tags WHERE todo_id = $1;`, todoID, created.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO checklist_items (todo_id, name, position)
+			SELECT $2, name, position FROM checklist_items WHERE todo_id = $1;`, todoID, created.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO reminders (workspace_id, todo_id, user_id, offset_seconds, channel, webhook_url)
+			SELECT workspace_id, $2, user_id, offset_seconds, channel, webhook_url
+			FROM reminders
+			WHERE todo_id = $1
+			AND offset_seconds IS NOT NULL;`, todoID, created.ID); err != nil {
+		return err
+	}
+	if created.AssigneeID == nil {
+		return nil
+	}
+	return recordAssignment(tx, created.ID, userID, nil, created.AssigneeID)
+}
+
+// reanchorSeries restarts the series at todoID with rule, taking todoID's
+// current fields as the template for every later occurrence.
+func reanchorSeries(tx *sqlx.Tx, todoID string, rule *recurrence.Rule) error {
+	_, err := tx.Exec(`UPDATE todo_series s
+			SET rrule = $2,
+			    dtstart = t.expiring_at,
+			    first_occurrence = t.occurrence,
+			    name = t.name,
+			    description = t.description,
+			    priority = t.priority,
+			    updated_at = NOW()
+			FROM todos t
+			WHERE t.id = $1
+			AND s.id = t.series_id;`, todoID, rule.String())
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE todos l
+			SET name = t.name,
+			    description = t.description,
+			    priority = t.priority
+			FROM todos t
+			WHERE t.id = $1
+			AND l.series_id = t.series_id
+			AND l.occurrence > t.occurrence
+			AND NOT l.complete;`, todoID)
+	return err
+}
+
+func GetTodoRecurrence(userID, workspaceID, todoID string) (*models.TodoSeries, error) {
+	var series *models.TodoSeries
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var err error
+		series, err = getTodoSeries(tx, todoID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	next, _, ok, err := nextOccurrence(series)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		series.NextExpiringAt = &next
+	}
+	return series, nil
+}
+
+// SetTodoRecurrence makes todoID recur by rrule starting from its due date.
+// For a todo that already recurs the new rule applies to this and all
+// future occurrences; earlier ones are left alone.
+func SetTodoRecurrence(userID, workspaceID, todoID, rrule string) (*models.TodoSeries, error) {
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		loc, err := userLocation(tx, userID)
+		if err != nil {
+			return err
+		}
+		rule, err := recurrence.Parse(rrule, loc)
+		if err != nil {
+			return err
+		}
+
+		_, err = getTodoSeries(tx, todoID)
+		if err == nil {
+			return reanchorSeries(tx, todoID, rule)
+		}
+		if !errors.Is(err, ErrNotRecurring) {
+			return err
+		}
+
+		var seriesID string
+		err = tx.Get(&seriesID, `INSERT INTO todo_series (workspace_id,user_id,rrule,dtstart,name,description,priority)
+				SELECT workspace_id,$2,$3,expiring_at,name,description,priority
+				FROM todos
+				WHERE id = $1
+				RETURNING id;`, todoID, userID, rule.String())
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE todos SET series_id = $2, occurrence = 0 WHERE id = $1;`, todoID, seriesID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetTodoRecurrence(userID, workspaceID, todoID)
+}
+
+// StopTodoRecurrence ends the series todoID belongs to. Existing
+// occurrences stay as plain todos.
+func StopTodoRecurrence(userID, workspaceID, todoID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`DELETE FROM todo_series s
+				USING todos t
+				WHERE t.id = $1
+				AND s.id = t.series_id;`, todoID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrNotRecurring
+		}
+		return nil
+	})
+}
+
+// UpdateTodoSeries is UpdateTodoById for this and all future occurrences:
+// the updated todo becomes the template the rest of the series follows.
+func UpdateTodoSeries(name, description, complete, priority string, expiringAt string, todoID, userID, workspaceID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		completed, err := updateTodo(tx, name, description, complete, priority, expiringAt, todoID, userID, workspaceID)
+		if err != nil {
+			return err
+		}
+		series, err := getTodoSeries(tx, todoID)
+		if err != nil {
+			return err
+		}
+		rule, _, err := seriesRule(series)
+		if err != nil {
+			return err
+		}
+		// COUNT now counts from this occurrence, so the total stays the same
+		if rule.Count > 0 {
+			rule.Count = max(rule.Count-(series.Occurrence-series.FirstOccurrence), 1)
+		}
+		if err := reanchorSeries(tx, todoID, rule); err != nil {
+			return err
+		}
+		if !completed {
+			return nil
+		}
+		return spawnNextOccurrence(tx, userID, todoID)
+	})
+}
@@ -0,0 +1,112 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token already used")
+)
+
+func CreateRefreshToken(sessionID string) (string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := insertRefreshToken(database.Todo, sessionID, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func insertRefreshToken(db sqlx.Execer, sessionID, token string) error {
+	SQL := `INSERT INTO refresh_tokens(session_id, token_hash, expires_at)
+			VALUES ($1, $2, $3);`
+	_, err := db.Exec(SQL, sessionID, utils.HashToken(token), time.Now().Add(utils.RefreshTokenTTL))
+	return err
+}
+
+// presenting an already used token revokes the whole session family
+func RotateRefreshToken(token string) (*models.RefreshTokenRotation, error) {
+	var (
+		rotation *models.RefreshTokenRotation
+		reused   bool
+	)
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		SQL := `SELECT rt.id,
+				       rt.session_id,
+				       us.user_id,
+				       rt.used_at,
+				       rt.expires_at,
+				       us.archived_at,
+				       (us.expires_at > NOW() AND us.last_seen_at > NOW() - make_interval(secs => $2)) AS session_active
+				FROM refresh_tokens rt
+				JOIN user_session us ON us.id = rt.session_id
+				WHERE rt.token_hash = $1
+				FOR UPDATE OF rt;`
+
+		var current models.RefreshToken
+		if err := tx.Get(&current, SQL, utils.HashToken(token), utils.SessionIdleTimeout().Seconds()); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if current.SessionArchivedAt != nil || !current.SessionActive {
+			return ErrInvalidRefreshToken
+		}
+
+		if current.UsedAt != nil {
+			reused = true
+			return revokeSession(tx, current.SessionID)
+		}
+		if current.ExpiresAt.Before(time.Now()) {
+			return ErrInvalidRefreshToken
+		}
+
+		if _, err := tx.Exec(`UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1;`, current.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE user_session SET last_seen_at = NOW() WHERE id = $1;`, current.SessionID); err != nil {
+			return err
+		}
+
+		next, err := utils.GenerateRandomToken()
+		if err != nil {
+			return err
+		}
+		if err := insertRefreshToken(tx, current.SessionID, next); err != nil {
+			return err
+		}
+		rotation = &models.RefreshTokenRotation{
+			UserID:       current.UserID,
+			SessionID:    current.SessionID,
+			RefreshToken: next,
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	return rotation, nil
+}
+
+func revokeSession(tx *sqlx.Tx, sessionID string) error {
+	SQL := `UPDATE user_session
+			SET archived_at = NOW()
+			WHERE id = $1
+			AND archived_at IS NULL;`
+	_, err := tx.Exec(SQL, sessionID)
+	return err
+}
@@ -0,0 +1,238 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var ErrReminderNotFound = errors.New("reminder not found")
+
+const maxReminderBackoff = 24 * time.Hour
+
+// reminderDueAt expects reminders as r and todos as t.
+const reminderDueAt = `COALESCE(r.remind_at, t.expiring_at - r.offset_seconds * INTERVAL '1 second')`
+
+const reminderColumns = `r.id, r.todo_id, r.user_id, r.remind_at, r.offset_seconds, r.channel, r.webhook_url,
+			       r.status, r.attempts, r.retry_at, r.sent_at, ` + reminderDueAt + ` AS due_at, r.created_at`
+
+func getReminder(q sqlx.Queryer, userID, todoID, reminderID string) (*models.Reminder, error) {
+	SQL := `SELECT ` + reminderColumns + `
+			FROM reminders r
+			JOIN todos t ON t.id = r.todo_id
+			WHERE r.id = $1
+			AND r.todo_id = $2
+			AND r.user_id = $3;`
+
+	var reminder models.Reminder
+	if err := sqlx.Get(q, &reminder, SQL, reminderID, todoID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReminderNotFound
+		}
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+// GetReminders returns the caller's own reminders on a todo.
+func GetReminders(userID, workspaceID, todoID string) ([]models.Reminder, error) {
+	SQL := `SELECT ` + reminderColumns + `
+			FROM reminders r
+			JOIN todos t ON t.id = r.todo_id
+			WHERE r.todo_id = $1
+			AND r.user_id = $2
+			ORDER BY due_at, r.id;`
+
+	reminders := make([]models.Reminder, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		return tx.Select(&reminders, SQL, todoID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// CreateReminder only needs read access: reminders are personal and anyone
+// who can see a todo may be reminded about it.
+func CreateReminder(userID, workspaceID, todoID string, req models.CreateReminderRequest) (*models.Reminder, error) {
+	SQL := `INSERT INTO reminders(workspace_id, todo_id, user_id, remind_at, offset_seconds, channel, webhook_url)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id;`
+
+	channel := req.Channel
+	if channel == "" {
+		channel = models.ReminderChannelInApp
+	}
+
+	var reminder *models.Reminder
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var reminderID string
+		if err := tx.Get(&reminderID, SQL, workspaceID, todoID, userID, req.RemindAt, req.OffsetSeconds,
+			channel, nullIfEmpty(req.WebhookURL)); err != nil {
+			return err
+		}
+		var err error
+		reminder, err = getReminder(tx, userID, todoID, reminderID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+func DeleteReminder(userID, workspaceID, todoID, reminderID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`DELETE FROM reminders
+				WHERE id = $1
+				AND todo_id = $2
+				AND user_id = $3;`, reminderID, todoID, userID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrReminderNotFound
+		}
+		return nil
+	})
+}
+func GetReminderDeliveries(userID, workspaceID, todoID, reminderID string) ([]models.ReminderDelivery, error) {
+	SQL := `SELECT id, reminder_id, attempt, channel, error, created_at
+			FROM reminder_deliveries
+			WHERE reminder_id = $1
+			ORDER BY attempt;`
+
+	deliveries := make([]models.ReminderDelivery, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTodoRole(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		if _, err := getReminder(tx, userID, todoID, reminderID); err != nil {
+			return err
+		}
+		return tx.Select(&deliveries, SQL, reminderID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// DeliverDueReminders claims up to limit due reminders and hands each to
+// deliver. Claiming marks the rows 'sending' until lease runs out and
+// commits straight away, so no lock is held while deliver waits on the
+// network and several workers can run at once without sending a reminder
+// twice. A failed attempt is retried with exponential backoff until
+// maxAttempts.
+func DeliverDueReminders(limit, maxAttempts int, backoff, lease time.Duration, deliver func(models.DueReminder) error) (int, error) {
+	due, err := claimDueReminders(limit, lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, reminder := range due {
+		if err := recordReminderDelivery(reminder, deliver(reminder), maxAttempts, backoff); err != nil {
+			return len(due), err
+		}
+	}
+	return len(due), nil
+}
+
+// claimDueReminders also picks up reminders whose lease expired, left behind
+// by a worker that stopped mid-batch.
+func claimDueReminders(limit int, lease time.Duration) ([]models.DueReminder, error) {
+	SQL := `SELECT r.id, r.todo_id, r.user_id, u.email, u.timezone, r.channel, r.webhook_url, r.attempts,
+			       t.name AS todo_name, t.expiring_at
+			FROM reminders r
+			JOIN todos t ON t.id = r.todo_id
+			JOIN users u ON u.id = r.user_id AND u.archived_at IS NULL
+			JOIN list_members m ON m.list_id = t.list_id AND m.user_id = r.user_id
+			WHERE NOT t.complete
+			AND ((r.status = 'pending' AND COALESCE(r.retry_at, ` + reminderDueAt + `) <= NOW())
+				OR (r.status = 'sending' AND r.retry_at <= NOW()))
+			ORDER BY COALESCE(r.retry_at, ` + reminderDueAt + `)
+			LIMIT $1
+			FOR UPDATE OF r SKIP LOCKED;`
+
+	var due []models.DueReminder
+	err := database.WithoutTenant(func(tx *sqlx.Tx) error {
+		if err := tx.Select(&due, SQL, limit); err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(due))
+		for _, reminder := range due {
+			ids = append(ids, reminder.ID)
+		}
+		_, err := tx.Exec(`UPDATE reminders
+				SET status = 'sending',
+				    retry_at = NOW() + $2 * INTERVAL '1 second'
+				WHERE id = ANY($1);`, pq.Array(ids), lease.Seconds())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+func recordReminderDelivery(reminder models.DueReminder, deliveryErr error, maxAttempts int, backoff time.Duration) error {
+	attempt := reminder.Attempts + 1
+	var errorMessage *string
+	if deliveryErr != nil {
+		message := deliveryErr.Error()
+		errorMessage = &message
+	}
+
+	status := models.ReminderStatusSent
+	var retryAt, sentAt *time.Time
+	now := time.Now()
+	switch {
+	case deliveryErr == nil:
+		sentAt = &now
+	case attempt >= maxAttempts:
+		status = models.ReminderStatusFailed
+	default:
+		status = models.ReminderStatusPending
+		next := now.Add(reminderRetryDelay(backoff, attempt))
+		retryAt = &next
+	}
+
+	return database.WithoutTenant(func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`INSERT INTO reminder_deliveries(reminder_id, attempt, channel, error)
+				VALUES ($1, $2, $3, $4);`, reminder.ID, attempt, reminder.Channel, errorMessage); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE reminders
+				SET status = $2, attempts = $3, retry_at = $4, sent_at = $5
+				WHERE id = $1;`, reminder.ID, status, attempt, retryAt, sentAt)
+		return err
+	})
+}
+
+func reminderRetryDelay(backoff time.Duration, attempt int) time.Duration {
+	delay := backoff
+	for i := 1; i < attempt && delay < maxReminderBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxReminderBackoff)
+}
@@ -0,0 +1,107 @@
+package dbHelper
+
+import (
+	"database/sql"
+
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func TouchSession(sessionID, userAgent, clientIP string) error {
+	SQL := `UPDATE user_session
+			SET last_seen_at = NOW(),
+			    user_agent = $2,
+			    client_ip = $3
+			WHERE id = $1
+			AND archived_at IS NULL
+			AND (last_seen_at IS NULL OR last_seen_at < NOW() - INTERVAL '1 minute'
+			     OR user_agent <> $2 OR client_ip <> $3);`
+	_, err := database.Todo.Exec(SQL, sessionID, userAgent, clientIP)
+	return err
+}
+func GetActiveSessions(userID string) ([]models.Session, error) {
+	SQL := `SELECT id,
+			       created_at,
+			       last_seen_at,
+			       expires_at,
+			       user_agent,
+			       client_ip
+			FROM user_session
+			WHERE user_id = $1
+			AND archived_at IS NULL
+			AND expires_at > NOW()
+			AND last_seen_at > NOW() - make_interval(secs => $2)
+			ORDER BY last_seen_at DESC;`
+
+	sessions := make([]models.Session, 0)
+	err := database.Todo.Select(&sessions, SQL, userID, utils.SessionIdleTimeout().Seconds())
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+func GetSessionByID(userID, sessionID string) (*models.Session, error) {
+	SQL := `SELECT id,
+			       created_at,
+			       last_seen_at,
+			       expires_at,
+			       user_agent,
+			       client_ip
+			FROM user_session
+			WHERE id = $1
+			AND user_id = $2
+			AND archived_at IS NULL;`
+
+	var session models.Session
+	err := database.Todo.Get(&session, SQL, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+func RevokeSession(userID, sessionID string) error {
+	SQL := `UPDATE user_session
+			SET archived_at = NOW()
+			WHERE id = $1
+			AND user_id = $2
+			AND archived_at IS NULL;`
+
+	result, err := database.Todo.Exec(SQL, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+func RevokeOtherSessions(userID, currentSessionID string) (int64, error) {
+	SQL := `UPDATE user_session
+			SET archived_at = NOW()
+			WHERE user_id = $1
+			AND id <> $2
+			AND archived_at IS NULL;`
+
+	result, err := database.Todo.Exec(SQL, userID, currentSessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+func ArchiveStaleSessions() (int64, error) {
+	SQL := `UPDATE user_session
+			SET archived_at = NOW()
+			WHERE archived_at IS NULL
+			AND (expires_at <= NOW() OR last_seen_at <= NOW() - make_interval(secs => $1));`
+
+	result, err := database.Todo.Exec(SQL, utils.SessionIdleTimeout().Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,237 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("a tag with this name already exists")
+	ErrInvalidTag  = errors.New("tag belongs to a different list")
+)
+
+// matchedTagsCount counts how many of the requested tag ids or lowercased
+// names ($8) a todo t carries. Other users' personal tags never match;
+// list tags always belong to the todo's own list.
+const matchedTagsCount = `(
+				SELECT count(DISTINCT f.value)
+				FROM todo_tags tt
+				JOIN tags tg ON tg.id = tt.tag_id
+				CROSS JOIN unnest($8::TEXT[]) AS f(value)
+				WHERE tt.todo_id = t.id
+				AND (tg.owner_id = $1 OR tg.list_id IS NOT NULL)
+				AND (tg.id::TEXT = f.value OR LOWER(tg.name) = f.value)
+			)`
+
+const tagColumns = `tg.id, tg.owner_id, tg.list_id, tg.name, tg.color, tg.created_at`
+
+func isUniqueTagViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" &&
+		(pqErr.Constraint == "unique_user_tag" || pqErr.Constraint == "unique_list_tag")
+}
+
+func loadTodoTags(q sqlx.Queryer, userID string, todos []models.Todos) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(todos))
+	ids := make([]string, len(todos))
+	for i := range todos {
+		index[todos[i].Id] = i
+		ids[i] = todos[i].Id
+		todos[i].Tags = make([]models.TodoTag, 0)
+	}
+	var tags []models.TodoTag
+	if err := sqlx.Select(q, &tags, `SELECT tt.todo_id, tg.id, tg.name, tg.color
+			FROM todo_tags tt
+			JOIN tags tg ON tg.id = tt.tag_id
+			WHERE tt.todo_id = ANY($1)
+			AND (tg.owner_id = $2 OR tg.list_id IS NOT NULL)
+			ORDER BY LOWER(tg.name);`, pq.Array(ids), userID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		i := index[tag.TodoID]
+		todos[i].Tags = append(todos[i].Tags, tag)
+	}
+	return nil
+}
+
+// GetTags returns the user's own tags and the tags of lists they belong
+// to. With listID set, only tags usable on that list are returned.
+func GetTags(userID, workspaceID, listID string) ([]models.Tag, error) {
+	SQL := `SELECT ` + tagColumns + `
+			FROM tags tg
+			WHERE tg.workspace_id = $2
+			AND (
+			    tg.owner_id = $1
+			    OR tg.list_id IN (SELECT list_id FROM list_members WHERE user_id = $1)
+			)
+			AND (
+			    $3::UUID IS NULL OR tg.owner_id IS NOT NULL OR tg.list_id = $3
+			)
+			ORDER BY tg.list_id NULLS FIRST, LOWER(tg.name);`
+
+	tags := make([]models.Tag, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if listID != "" {
+			if _, err := getListRole(tx, userID, workspaceID, listID); err != nil {
+				return err
+			}
+		}
+		return tx.Select(&tags, SQL, userID, workspaceID, nullIfEmpty(listID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+func CreateTag(userID, workspaceID string, req models.CreateTagRequest) (*models.Tag, error) {
+	SQL := `INSERT INTO tags(workspace_id, owner_id, list_id, name, color)
+			VALUES ($1, $2, $3, $4, LOWER($5))
+			RETURNING id, owner_id, list_id, name, color, created_at;`
+
+	var tag models.Tag
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		ownerID := &userID
+		if req.ListId != "" {
+			if err := requireListWrite(tx, userID, workspaceID, req.ListId); err != nil {
+				return err
+			}
+			ownerID = nil
+		}
+		return tx.Get(&tag, SQL, workspaceID, ownerID, nullIfEmpty(req.ListId), req.Name, req.Color)
+	})
+	if err != nil {
+		if isUniqueTagViolation(err) {
+			return nil, ErrTagExists
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// getTagForWrite returns a tag the user may edit: their own tag, or a tag
+// of a list they can write to.
+func getTagForWrite(tx *sqlx.Tx, userID, workspaceID, tagID string) (*models.Tag, error) {
+	var tag models.Tag
+	err := tx.Get(&tag, `SELECT `+tagColumns+`
+			FROM tags tg
+			WHERE tg.id = $1
+			AND tg.workspace_id = $2;`, tagID, workspaceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	if tag.OwnerID != nil {
+		if *tag.OwnerID != userID {
+			return nil, ErrTagNotFound
+		}
+		return &tag, nil
+	}
+	if err := requireListWrite(tx, userID, workspaceID, *tag.ListID); err != nil {
+		if errors.Is(err, ErrListNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+func UpdateTag(userID, workspaceID, tagID, name, color string) (*models.Tag, error) {
+	var tag *models.Tag
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTagForWrite(tx, userID, workspaceID, tagID); err != nil {
+			return err
+		}
+		tag = &models.Tag{}
+		return tx.Get(tag, `UPDATE tags
+				SET name = $1,
+				    color = LOWER($2)
+				WHERE id = $3
+				RETURNING id, owner_id, list_id, name, color, created_at;`, name, color, tagID)
+	})
+	if err != nil {
+		if isUniqueTagViolation(err) {
+			return nil, ErrTagExists
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+func DeleteTag(userID, workspaceID, tagID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if _, err := getTagForWrite(tx, userID, workspaceID, tagID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`DELETE FROM tags WHERE id = $1;`, tagID)
+		return err
+	})
+}
+
+// AddTodoTag attaches one of the user's own tags, or a tag of the todo's
+// list, to the todo.
+func AddTodoTag(userID, workspaceID, todoID, tagID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		var tag struct {
+			OwnerID    *string `db:"owner_id"`
+			ListID     *string `db:"list_id"`
+			TodoListID string  `db:"todo_list_id"`
+		}
+		err := tx.Get(&tag, `SELECT tg.owner_id, tg.list_id, t.list_id AS todo_list_id
+				FROM tags tg, todos t
+				WHERE tg.id = $1
+				AND t.id = $2;`, tagID, todoID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrTagNotFound
+			}
+			return err
+		}
+		if tag.OwnerID != nil && *tag.OwnerID != userID {
+			return ErrTagNotFound
+		}
+		if tag.ListID != nil && *tag.ListID != tag.TodoListID {
+			return ErrInvalidTag
+		}
+		_, err = tx.Exec(`INSERT INTO todo_tags(todo_id, tag_id)
+				VALUES ($1, $2)
+				ON CONFLICT DO NOTHING;`, todoID, tagID)
+		return err
+	})
+}
+func RemoveTodoTag(userID, workspaceID, todoID, tagID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`DELETE FROM todo_tags tt
+				USING tags tg
+				WHERE tt.tag_id = tg.id
+				AND tt.todo_id = $1
+				AND tt.tag_id = $2
+				AND (tg.owner_id = $3 OR tg.list_id IS NOT NULL);`, todoID, tagID, userID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrTagNotFound
+		}
+		return nil
+	})
+}
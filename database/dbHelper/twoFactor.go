@@ -0,0 +1,267 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/totp"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+const maxChallengeAttempts = 5
+
+var (
+	ErrTwoFactorNotPending = errors.New("two-factor setup not started")
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactor    = errors.New("invalid two-factor code")
+	ErrInvalidChallenge    = errors.New("invalid or expired login challenge")
+)
+
+func GetTwoFactorState(userID string) (*models.TwoFactorState, error) {
+	SQL := `SELECT email,
+			       totp_secret,
+			       totp_pending_secret,
+			       totp_enabled_at,
+			       totp_last_step
+			FROM users
+			WHERE id = $1
+			AND archived_at IS NULL;`
+
+	var state models.TwoFactorState
+	err := database.Todo.Get(&state, SQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+func IsTwoFactorEnabled(userID string) (bool, error) {
+	SQL := `SELECT totp_enabled_at IS NOT NULL
+			FROM users
+			WHERE id = $1;`
+
+	var enabled bool
+	err := database.Todo.Get(&enabled, SQL, userID)
+	return enabled, err
+}
+func SetPendingTOTPSecret(userID, secret string) error {
+	SQL := `UPDATE users
+			SET totp_pending_secret = $1
+			WHERE id = $2
+			AND archived_at IS NULL;`
+
+	_, err := database.Todo.Exec(SQL, secret, userID)
+	return err
+}
+
+// EnableTwoFactor promotes the pending secret once code proves the user
+// holds it, and replaces any recovery codes with fresh ones.
+func EnableTwoFactor(userID, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := database.Tx(func(tx *sqlx.Tx) error {
+		var pending sql.NullString
+		if err := tx.Get(&pending, `SELECT totp_pending_secret
+				FROM users
+				WHERE id = $1
+				AND archived_at IS NULL
+				FOR UPDATE;`, userID); err != nil {
+			return err
+		}
+		if !pending.Valid || pending.String == "" {
+			return ErrTwoFactorNotPending
+		}
+		step, ok := totp.Validate(pending.String, code, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactor
+		}
+		if _, err := tx.Exec(`UPDATE users
+				SET totp_secret = totp_pending_secret,
+				    totp_pending_secret = NULL,
+				    totp_enabled_at = NOW(),
+				    totp_last_step = $2
+				WHERE id = $1;`, userID, step); err != nil {
+			return err
+		}
+
+		codes, err := replaceRecoveryCodes(tx, userID)
+		if err != nil {
+			return err
+		}
+		recoveryCodes = codes
+		return nil
+	})
+	return recoveryCodes, err
+}
+func DisableTwoFactor(userID, code string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := verifySecondFactor(tx, userID, code); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE users
+				SET totp_secret = NULL,
+				    totp_pending_secret = NULL,
+				    totp_enabled_at = NULL,
+				    totp_last_step = 0
+				WHERE id = $1;`, userID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1;`, userID)
+		return err
+	})
+}
+func CreateLoginChallenge(userID string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	SQL := `INSERT INTO login_challenges(user_id, token_hash, expires_at)
+			VALUES ($1, $2, $3);`
+	_, err = database.Todo.Exec(SQL, userID, utils.HashToken(token), time.Now().Add(ttl))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetLoginChallengeUser returns the user and email a pending challenge belongs to.
+func GetLoginChallengeUser(challengeToken string) (string, string, error) {
+	SQL := `SELECT u.id, u.email
+			FROM login_challenges c
+			JOIN users u ON u.id = c.user_id
+			WHERE c.token_hash = $1
+			AND c.used_at IS NULL
+			AND c.expires_at > NOW();`
+
+	var user struct {
+		ID    string `db:"id"`
+		Email string `db:"email"`
+	}
+	err := database.Todo.Get(&user, SQL, utils.HashToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", ErrInvalidChallenge
+		}
+		return "", "", err
+	}
+	return user.ID, user.Email, nil
+}
+
+// CompleteLoginChallenge consumes the challenge when code is a valid TOTP
+// or unused recovery code. Failed attempts are counted against the challenge.
+func CompleteLoginChallenge(challengeToken, code string) (string, error) {
+	var (
+		userID    string
+		codeErr   error
+		challenge struct {
+			ID       string `db:"id"`
+			UserID   string `db:"user_id"`
+			Attempts int    `db:"attempts"`
+		}
+	)
+	err := database.Tx(func(tx *sqlx.Tx) error {
+		if err := tx.Get(&challenge, `SELECT id, user_id, attempts
+				FROM login_challenges
+				WHERE token_hash = $1
+				AND used_at IS NULL
+				AND expires_at > NOW()
+				FOR UPDATE;`, utils.HashToken(challengeToken)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidChallenge
+			}
+			return err
+		}
+		if challenge.Attempts >= maxChallengeAttempts {
+			return ErrInvalidChallenge
+		}
+
+		// a wrong code must still commit the attempt counter
+		codeErr = verifySecondFactor(tx, challenge.UserID, code)
+		if codeErr != nil && !errors.Is(codeErr, ErrInvalidTwoFactor) {
+			return codeErr
+		}
+		if codeErr != nil {
+			_, err := tx.Exec(`UPDATE login_challenges SET attempts = attempts + 1 WHERE id = $1;`, challenge.ID)
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE login_challenges SET used_at = NOW() WHERE id = $1;`, challenge.ID); err != nil {
+			return err
+		}
+		userID = challenge.UserID
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if codeErr != nil {
+		return "", codeErr
+	}
+	return userID, nil
+}
+
+func verifySecondFactor(tx *sqlx.Tx, userID, code string) error {
+	var state models.TwoFactorState
+	if err := tx.Get(&state, `SELECT email,
+			       totp_secret,
+			       totp_pending_secret,
+			       totp_enabled_at,
+			       totp_last_step
+			FROM users
+			WHERE id = $1
+			AND archived_at IS NULL
+			FOR UPDATE;`, userID); err != nil {
+		return err
+	}
+	if state.EnabledAt == nil || state.Secret == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := totp.ValidateAfter(*state.Secret, code, time.Now(), state.LastStep); ok {
+		_, err := tx.Exec(`UPDATE users SET totp_last_step = $2 WHERE id = $1;`, userID, step)
+		return err
+	}
+
+	result, err := tx.Exec(`UPDATE recovery_codes
+			SET used_at = NOW()
+			WHERE user_id = $1
+			AND code_hash = $2
+			AND used_at IS NULL;`, userID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrInvalidTwoFactor
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(tx *sqlx.Tx, userID string) ([]string, error) {
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1;`, userID); err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, utils.RecoveryCodeCount)
+	for i := 0; i < utils.RecoveryCodeCount; i++ {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`INSERT INTO recovery_codes(user_id, code_hash) VALUES ($1, $2);`,
+			userID, utils.HashToken(normalizeRecoveryCode(code))); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
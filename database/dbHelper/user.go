@@ -3,9 +3,13 @@ package dbHelper
 import (
 	"database/sql"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/nikhilpratapgit/TodoApp/database"
 	"github.com/nikhilpratapgit/TodoApp/models"
 	"github.com/nikhilpratapgit/TodoApp/utils"
@@ -28,34 +32,42 @@ func CreateUser(name, email, password string) (string, error) {
 	err := database.Todo.Get(&userID, SQL, name, email, password)
 	return userID, err
 }
-func CreateUserSession(userID string) (string, error) {
-	SQL := `INSERT INTO user_session(user_id)
-			VALUES ($1) RETURNING id;`
+func CreateUserSession(userID, userAgent, clientIP string) (string, error) {
+	SQL := `INSERT INTO user_session(user_id, user_agent, client_ip, expires_at)
+			VALUES ($1, $2, $3, $4) RETURNING id;`
 	var sessionID string
-	err := database.Todo.Get(&sessionID, SQL, userID)
+	err := database.Todo.Get(&sessionID, SQL, userID, userAgent, clientIP, time.Now().Add(utils.SessionLifetime()))
 	if err != nil {
 		return "", err
 	}
 	return sessionID, nil
 }
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+
+	// compared against when the email is unknown so both paths cost one bcrypt
+	dummyPasswordHash, _ = utils.HashPassword("dummy-password-for-timing")
+)
+
 func GetUserByEmail(email, password string) (string, error) {
 	SQL := `
 		SELECT id, password
 		FROM users
-		WHERE email = $1 AND archived_at IS NULL
-		RETURNING id,password;
+		WHERE email = TRIM(LOWER($1)) AND archived_at IS NULL;
 	`
 
 	var user models.UserAuth
 	err := database.Todo.Get(&user, SQL, email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", errors.New("no user exist")
+			_ = utils.CheckPassword(dummyPasswordHash, password)
+			return "", ErrInvalidCredentials
 		}
 		return "", err
 	}
 	if err := utils.CheckPassword(user.Password, password); err != nil {
-		return "", errors.New("invalid credentials")
+		return "", ErrInvalidCredentials
 	}
 	return user.ID, nil
 }
@@ -93,88 +105,242 @@ func DeleteSessionByToken(Token string) error {
 //		}
 //		return userID, nil
 //	}
-func CreateTodo(userID, name, description string, expiringAt time.Time) (*models.Todos, error) {
-	SQL := `INSERT INTO todos (user_id,name,description,expiring_at) 
-			VALUES ($1,$2,$3,$4) RETURNING id,created_at;`
+var ErrTodoNotFound = errors.New("todo not found")
+
+const todoColumns = `t.id,t.user_id,t.list_id,t.assignee_id,t.parent_id,t.series_id,t.occurrence,t.name,t.description,t.complete,t.priority,t.position,t.expiring_at,t.created_at,t.updated_at`
+
+// CreateTodo expects req.ListId to be resolved by the caller.
+func CreateTodo(userID, workspaceID string, req models.CreateTodo) (*models.Todos, error) {
+	SQL := `INSERT INTO todos (user_id,workspace_id,list_id,assignee_id,parent_id,name,description,priority,expiring_at,position) 
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,(SELECT COALESCE(MAX(position), 0) + $10 FROM todos WHERE list_id = $3))
+			RETURNING id,complete,position,created_at,updated_at;`
 	todo := &models.Todos{
 		UserId:      userID,
-		Name:        name,
-		Description: description,
-		ExpiringAt:  expiringAt,
+		ListId:      req.ListId,
+		AssigneeId:  nullIfEmpty(req.AssigneeId),
+		ParentId:    nullIfEmpty(req.ParentId),
+		Name:        req.Name,
+		Description: req.Description,
+		Priority:    req.Priority,
+		ExpiringAt:  req.ExpiringAt,
+		Tags:        make([]models.TodoTag, 0),
+	}
+	if todo.Priority == "" {
+		todo.Priority = models.PriorityNone
 	}
-	err := database.Todo.QueryRow(SQL, userID, name, description, expiringAt).Scan(&todo.Id, &todo.CreatedAt)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireListWrite(tx, userID, workspaceID, req.ListId); err != nil {
+			return err
+		}
+		if req.AssigneeId != "" {
+			if err := requireAssignable(tx, req.ListId, req.AssigneeId); err != nil {
+				return err
+			}
+		}
+		if req.ParentId != "" {
+			if err := requireParent(tx, req.ListId, "", req.ParentId); err != nil {
+				return err
+			}
+		}
+		err := tx.QueryRow(SQL, userID, workspaceID, req.ListId, todo.AssigneeId, todo.ParentId, req.Name, req.Description,
+			todo.Priority, req.ExpiringAt, positionGap).
+			Scan(&todo.Id, &todo.Complete, &todo.Position, &todo.CreatedAt, &todo.UpdatedAt)
+		if err != nil || req.AssigneeId == "" {
+			return err
+		}
+		return recordAssignment(tx, todo.Id, userID, nil, todo.AssigneeId)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return todo, nil
 }
-func GetTodos(userID, name string, date time.Time, complete bool) ([]models.Todos, error) {
+func GetTodos(userID, workspaceID string, filter models.TodoFilter) ([]models.Todos, error) {
 	SQL := `
-			SELECT id,
-			       user_id,
-			       name,
-			       description,
-			       complete,
-			       expiring_at,
-				   created_at
-			FROM todos
-			WHERE user_id =$1
+			SELECT ` + todoColumns + `,
+			       ` + checklistProgressColumns + `
+			FROM todos t
+			JOIN list_members m ON m.list_id = t.list_id AND m.user_id = $1
+			JOIN lists l ON l.id = t.list_id AND l.archived_at IS NULL
+			` + checklistProgressJoin + `
+			WHERE t.workspace_id = $6
 			AND (
-			    $2::boolean IS NULL or complete=$2
+			    $5::UUID IS NULL OR t.list_id = $5
 			)
 			AND (
-			    $3::TIMESTAMPTZ IS NULL or expiring_at<=$3
+			    $2::boolean IS NULL or t.complete=$2
 			)
 			AND (
-			    $4::TEXT IS NULL OR name LIKE'%'||$4||'%'
+			    $3::TIMESTAMPTZ IS NULL or t.expiring_at<=$3
 			)
-			order by expiring_at
-			`
+			AND (
+			    $4::TEXT IS NULL OR t.name LIKE'%'||$4||'%'
+			)
+			AND (
+			    $7::UUID IS NULL OR t.assignee_id = $7
+			)
+			AND (
+			    cardinality($8::TEXT[]) = 0 OR ` + matchedTagsCount + ` >= CASE WHEN $9 THEN 1 ELSE cardinality($8::TEXT[]) END
+			)
+			ORDER BY ` + todoOrderBy(filter.Sort)
 	var todos []models.Todos
 
-	err := database.Todo.Select(&todos, SQL, userID, complete, date, name)
+	// a zero time means no due date filter
+	var expiringAt *time.Time
+	if !filter.ExpiringAt.IsZero() {
+		expiringAt = &filter.ExpiringAt
+	}
+	// the AND match counts distinct tags, so a repeated tag must only be
+	// asked for once
+	tags := make([]string, 0, len(filter.Tags))
+	for _, tag := range filter.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := tx.Select(&todos, SQL, userID, filter.Complete, expiringAt, filter.Search, nullIfEmpty(filter.ListID),
+			workspaceID, nullIfEmpty(filter.AssigneeID), pq.Array(tags), filter.MatchAnyTag); err != nil {
+			return err
+		}
+		return loadTodoTags(tx, userID, todos)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return todos, nil
 }
-func GetTodoByID(todoID, userID string) (*models.Todos, error) {
-	SQL := `SELECT id,user_id,name,description,complete,expiring_at,created_at
-			FROM todos where id = $1 
-			AND user_id=$2 `
+func GetTodoByID(todoID, userID, workspaceID string) (*models.Todos, error) {
+	SQL := `SELECT ` + todoColumns + `,
+			       ` + checklistProgressColumns + `
+			FROM todos t
+			JOIN list_members m ON m.list_id = t.list_id AND m.user_id = $2
+			JOIN lists l ON l.id = t.list_id AND l.archived_at IS NULL
+			` + checklistProgressJoin + `
+			WHERE t.id = $1
+			AND t.workspace_id = $3`
 	var todo models.Todos
 
-	err := database.Todo.Get(&todo, SQL, todoID, userID)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := tx.Get(&todo, SQL, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		todos := []models.Todos{todo}
+		if err := loadTodoTags(tx, userID, todos); err != nil {
+			return err
+		}
+		todo = todos[0]
+		return nil
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, err
 	}
 	return &todo, nil
 }
-func DeleteTodoById(userID, todoID string) error {
-	SQL := `DELETE FROM todos WHERE id=$1 AND user_id =$2;`
 
-	_, err := database.Todo.Exec(SQL, todoID, userID)
+func getTodoRole(q sqlx.Queryer, todoID, userID, workspaceID string) (string, error) {
+	SQL := `SELECT m.role
+			FROM todos t
+			JOIN list_members m ON m.list_id = t.list_id AND m.user_id = $2
+			JOIN lists l ON l.id = t.list_id AND l.archived_at IS NULL
+			WHERE t.id = $1
+			AND t.workspace_id = $3;`
+
+	var role string
+	err := sqlx.Get(q, &role, SQL, todoID, userID, workspaceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrTodoNotFound
+		}
+		return "", err
+	}
+	return role, nil
+}
+func requireTodoWrite(q sqlx.Queryer, todoID, userID, workspaceID string) error {
+	role, err := getTodoRole(q, todoID, userID, workspaceID)
 	if err != nil {
 		return err
 	}
+	if !CanWrite(role) {
+		return ErrListForbidden
+	}
 	return nil
 }
-func UpdateTodoById(name, description, complete string, expiringAt string, todoID, userID string) error {
-	SQL := `UPDATE todos 
-			SET name=$1,description=$2,complete=$3,expiring_at=$4
-			WHERE id=$5 
-			and user_id=$6;`
+func RequireTodoWrite(userID, workspaceID, todoID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		return requireTodoWrite(tx, todoID, userID, workspaceID)
+	})
+}
 
-	_, err := database.Todo.Exec(
-		SQL, name, description, complete, expiringAt, todoID, userID)
+// DeleteTodoById returns the storage keys of the todo's attachments so the
+// caller can delete the blobs once the rows are gone.
+func DeleteTodoById(userID, workspaceID, todoID string) ([]string, error) {
+	SQL := `DELETE FROM todos WHERE id=$1 AND workspace_id=$2;`
 
-	if err != nil {
+	storageKeys := make([]string, 0)
+	err := database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+			return err
+		}
+		if err := tx.Select(&storageKeys, `SELECT storage_key FROM attachments WHERE todo_id = $1;`, todoID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(SQL, todoID, workspaceID)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return storageKeys, nil
+}
+
+// UpdateTodoById keeps the current priority when priority is empty. Only
+// this occurrence of a recurring todo changes, see UpdateTodoSeries.
+func UpdateTodoById(name, description, complete, priority string, expiringAt string, todoID, userID, workspaceID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		completed, err := updateTodo(tx, name, description, complete, priority, expiringAt, todoID, userID, workspaceID)
+		if err != nil || !completed {
+			return err
+		}
+		return spawnNextOccurrence(tx, userID, todoID)
+	})
+}
+
+// updateTodo reports whether the update completed a todo that was open.
+func updateTodo(tx *sqlx.Tx, name, description, complete, priority string, expiringAt string, todoID, userID, workspaceID string) (bool, error) {
+	SQL := `UPDATE todos 
+			SET name=$1,description=$2,complete=$3,expiring_at=$4,priority=COALESCE(NULLIF($7, ''), priority)
+			WHERE id=$5
+			AND workspace_id=$6
+			RETURNING complete;`
+
+	if err := requireTodoWrite(tx, todoID, userID, workspaceID); err != nil {
+		return false, err
+	}
+	var wasComplete, isComplete bool
+	if err := tx.Get(&wasComplete, `SELECT complete FROM todos WHERE id = $1 FOR UPDATE;`, todoID); err != nil {
+		return false, err
+	}
+	// only completing is blocked, a todo that is already done stays editable
+	// when a new open blocker is added to it
+	if !wasComplete {
+		var blocked bool
+		if err := tx.Get(&blocked, `SELECT $2::BOOLEAN AND `+openBlockersCondition+`;`, todoID, complete); err != nil {
+			return false, err
+		}
+		if blocked {
+			return false, ErrTodoBlocked
+		}
+	}
+	if err := tx.Get(&isComplete, SQL, name, description, complete, expiringAt, todoID, workspaceID, priority); err != nil {
+		return false, err
+	}
+	return isComplete && !wasComplete, nil
 }
 
 //	func CompleteTodos(userID string) ([]models.Todos, error) {
@@ -222,16 +388,26 @@ func UpdateTodoById(name, description, complete string, expiringAt string, todoI
 //
 //		return todos, nil
 //	}
-func ValidateSession(sessionID string) (uuid.UUID, error) {
-	SQL := `SELECT user_id from user_session where id=$1 AND archived_at IS NULL;`
+func ValidateSession(sessionID string) (uuid.UUID, string, error) {
+	SQL := `SELECT us.user_id, u.role
+			FROM user_session us
+			JOIN users u ON u.id = us.user_id
+			WHERE us.id=$1
+			AND us.archived_at IS NULL
+			AND u.archived_at IS NULL
+			AND us.expires_at > NOW()
+			AND us.last_seen_at > NOW() - make_interval(secs => $2);`
 
-	var userID uuid.UUID
+	var session struct {
+		UserID uuid.UUID `db:"user_id"`
+		Role   string    `db:"role"`
+	}
 
-	err := database.Todo.Get(&userID, SQL, sessionID)
+	err := database.Todo.Get(&session, SQL, sessionID, utils.SessionIdleTimeout().Seconds())
 
 	if err != nil {
-		return uuid.Nil, errors.New("invalid session")
+		return uuid.Nil, "", errors.New("invalid session")
 	}
 
-	return userID, nil
+	return session.UserID, session.Role, nil
 }
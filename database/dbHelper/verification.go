@@ -0,0 +1,95 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+var ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+func CreateEmailVerificationToken(userID, email string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	SQL := `INSERT INTO email_verification_tokens(user_id, email, token_hash, expires_at)
+			VALUES ($1, TRIM(LOWER($2)), $3, $4);`
+	_, err = database.Todo.Exec(SQL, userID, email, utils.HashToken(token), time.Now().Add(ttl))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+func VerifyEmail(token string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		SQL := `SELECT user_id, email
+				FROM email_verification_tokens
+				WHERE token_hash = $1
+				AND used_at IS NULL
+				AND expires_at > NOW()
+				FOR UPDATE;`
+
+		var pending struct {
+			UserID string `db:"user_id"`
+			Email  string `db:"email"`
+		}
+		if err := tx.Get(&pending, SQL, utils.HashToken(token)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidVerificationToken
+			}
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE email_verification_tokens
+				SET used_at = NOW()
+				WHERE token_hash = $1;`, utils.HashToken(token)); err != nil {
+			return err
+		}
+
+		// the token only verifies the address it was issued for
+		result, err := tx.Exec(`UPDATE users
+				SET verified_at = NOW()
+				WHERE id = $1
+				AND email = $2
+				AND archived_at IS NULL;`, pending.UserID, pending.Email)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrInvalidVerificationToken
+		}
+		return nil
+	})
+}
+func GetUserVerification(email string) (*models.UserVerification, error) {
+	SQL := `SELECT id, email, verified_at
+			FROM users
+			WHERE email = TRIM(LOWER($1))
+			AND archived_at IS NULL;`
+
+	var user models.UserVerification
+	err := database.Todo.Get(&user, SQL, email)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+func IsUserVerified(userID string) (bool, error) {
+	SQL := `SELECT verified_at IS NOT NULL
+			FROM users
+			WHERE id = $1;`
+
+	var verified bool
+	err := database.Todo.Get(&verified, SQL, userID)
+	return verified, err
+}
@@ -0,0 +1,183 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/TodoApp/database"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+var (
+	ErrWorkspaceNotFound  = errors.New("workspace not found")
+	ErrWorkspaceForbidden = errors.New("insufficient workspace permissions")
+	ErrWorkspaceOwner     = errors.New("the workspace owner cannot be removed")
+	ErrMemberNotFound     = errors.New("no active user with this email")
+)
+
+func GetOrCreatePersonalWorkspace(userID string) (string, error) {
+	var workspaceID string
+	err := database.Tx(func(tx *sqlx.Tx) error {
+		err := tx.Get(&workspaceID, `INSERT INTO workspaces(name, personal_owner_id)
+				VALUES ('Personal', $1)
+				ON CONFLICT (personal_owner_id) WHERE personal_owner_id IS NOT NULL DO NOTHING
+				RETURNING id;`, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return tx.Get(&workspaceID, `SELECT id FROM workspaces WHERE personal_owner_id = $1;`, userID)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO workspace_members(workspace_id, user_id, role)
+				VALUES ($1, $2, 'owner');`, workspaceID, userID)
+		return err
+	})
+	return workspaceID, err
+}
+func getWorkspaceRole(q sqlx.Queryer, userID, workspaceID string) (string, error) {
+	SQL := `SELECT m.role
+			FROM workspace_members m
+			JOIN workspaces w ON w.id = m.workspace_id
+			WHERE m.workspace_id = $1
+			AND m.user_id = $2
+			AND w.archived_at IS NULL;`
+
+	var role string
+	err := sqlx.Get(q, &role, SQL, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrWorkspaceNotFound
+		}
+		return "", err
+	}
+	return role, nil
+}
+func requireWorkspaceAdmin(q sqlx.Queryer, userID, workspaceID string) error {
+	role, err := getWorkspaceRole(q, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if role != models.WorkspaceRoleOwner && role != models.WorkspaceRoleAdmin {
+		return ErrWorkspaceForbidden
+	}
+	return nil
+}
+func GetWorkspaceRole(userID, workspaceID string) (string, error) {
+	return getWorkspaceRole(database.Todo, userID, workspaceID)
+}
+func GetWorkspaces(userID string) ([]models.Workspace, error) {
+	SQL := `SELECT w.id, w.name, w.personal_owner_id IS NOT NULL AS is_personal, m.role, w.created_at
+			FROM workspaces w
+			JOIN workspace_members m ON m.workspace_id = w.id
+			WHERE m.user_id = $1
+			AND w.archived_at IS NULL
+			ORDER BY is_personal DESC, w.created_at;`
+
+	workspaces := make([]models.Workspace, 0)
+	err := database.Todo.Select(&workspaces, SQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+func CreateWorkspace(userID, name string) (*models.Workspace, error) {
+	workspace := &models.Workspace{}
+	err := database.Tx(func(tx *sqlx.Tx) error {
+		if err := tx.Get(workspace, `INSERT INTO workspaces(name)
+				VALUES ($1)
+				RETURNING id, name, FALSE AS is_personal, 'owner' AS role, created_at;`, name); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO workspace_members(workspace_id, user_id, role)
+				VALUES ($1, $2, 'owner');`, workspace.ID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+func GetWorkspaceMembers(userID, workspaceID string) ([]models.WorkspaceMember, error) {
+	SQL := `SELECT m.user_id, u.name, u.email, m.role, m.created_at
+			FROM workspace_members m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.workspace_id = $1
+			ORDER BY m.created_at;`
+
+	if _, err := GetWorkspaceRole(userID, workspaceID); err != nil {
+		return nil, err
+	}
+	members := make([]models.WorkspaceMember, 0)
+	err := database.Todo.Select(&members, SQL, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// AddWorkspaceMember adds an existing user or updates the role of a
+// current member. The owner's role is never changed.
+func AddWorkspaceMember(userID, workspaceID, email, role string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := requireWorkspaceAdmin(tx, userID, workspaceID); err != nil {
+			return err
+		}
+		var memberID string
+		err := tx.Get(&memberID, `SELECT id
+				FROM users
+				WHERE email = TRIM(LOWER($1))
+				AND archived_at IS NULL;`, email)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMemberNotFound
+			}
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO workspace_members(workspace_id, user_id, role)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (workspace_id, user_id) DO UPDATE
+				SET role = EXCLUDED.role
+				WHERE workspace_members.role <> 'owner';`, workspaceID, memberID, role)
+		return err
+	})
+}
+
+// RemoveWorkspaceMember also drops the member's access to shared lists in
+// the workspace. Any member may remove themselves, except the owner.
+func RemoveWorkspaceMember(userID, workspaceID, memberID string) error {
+	return database.WithWorkspace(workspaceID, func(tx *sqlx.Tx) error {
+		if memberID != userID {
+			if err := requireWorkspaceAdmin(tx, userID, workspaceID); err != nil {
+				return err
+			}
+		} else if _, err := getWorkspaceRole(tx, userID, workspaceID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`DELETE FROM workspace_members
+				WHERE workspace_id = $1
+				AND user_id = $2
+				AND role <> 'owner';`, workspaceID, memberID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			if _, err := getWorkspaceRole(tx, memberID, workspaceID); err == nil {
+				return ErrWorkspaceOwner
+			}
+			return sql.ErrNoRows
+		}
+		_, err = tx.Exec(`DELETE FROM list_members
+				WHERE user_id = $1
+				AND role <> 'owner'
+				AND list_id IN (SELECT id FROM lists WHERE workspace_id = $2);`, memberID, workspaceID)
+		if err != nil {
+			return err
+		}
+		return unassignMember(tx, userID, workspaceID, "", memberID)
+	})
+}
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	session_id UUID NOT NULL REFERENCES user_session(id),
+	token_hash TEXT NOT NULL,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	used_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_refresh_token ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_id ON refresh_tokens(session_id);
+
+COMMIT;
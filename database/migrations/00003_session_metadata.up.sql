@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE user_session
+	ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS client_ip TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS user_session_user_id ON user_session(user_id) WHERE archived_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+ALTER TABLE user_session
+	ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE user_session
+SET expires_at = created_at + INTERVAL '30 days'
+WHERE expires_at IS NULL;
+
+ALTER TABLE user_session
+	ALTER COLUMN expires_at SET NOT NULL;
+
+UPDATE user_session
+SET last_seen_at = created_at
+WHERE last_seen_at IS NULL;
+
+ALTER TABLE user_session
+	ALTER COLUMN last_seen_at SET NOT NULL;
+
+COMMIT;
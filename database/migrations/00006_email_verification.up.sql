@@ -0,0 +1,23 @@
+BEGIN;
+
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITH TIME ZONE;
+
+-- accounts created before verification existed are treated as verified
+UPDATE users
+SET verified_at = created_at
+WHERE verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id),
+	email TEXT NOT NULL,
+	token_hash TEXT NOT NULL,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	used_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_email_verification_token ON email_verification_tokens(token_hash);
+
+COMMIT;
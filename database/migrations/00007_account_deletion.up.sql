@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS purge_at TIMESTAMP WITH TIME ZONE,
+	ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS users_pending_purge ON users(purge_at) WHERE purged_at IS NULL AND purge_at IS NOT NULL;
+
+COMMIT;
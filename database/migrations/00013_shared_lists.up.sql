@@ -0,0 +1,66 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lists(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	owner_id UUID NOT NULL REFERENCES users(id),
+	name TEXT NOT NULL,
+	is_personal BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_personal_list ON lists(owner_id) WHERE is_personal;
+
+CREATE TABLE IF NOT EXISTS list_members(
+	list_id UUID NOT NULL REFERENCES lists(id),
+	user_id UUID NOT NULL REFERENCES users(id),
+	role TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS list_members_user_id ON list_members(user_id);
+
+CREATE TABLE IF NOT EXISTS list_invitations(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	list_id UUID NOT NULL REFERENCES lists(id),
+	email TEXT NOT NULL,
+	role TEXT NOT NULL CHECK (role IN ('editor', 'viewer')),
+	token_hash TEXT NOT NULL,
+	invited_by UUID NOT NULL REFERENCES users(id),
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	accepted_at TIMESTAMP WITH TIME ZONE,
+	revoked_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_list_invitation ON list_invitations(token_hash);
+
+-- every user that already has todos gets a personal list holding them
+INSERT INTO lists(owner_id, name, is_personal)
+SELECT DISTINCT user_id, 'Personal', TRUE
+FROM todos
+ON CONFLICT DO NOTHING;
+
+INSERT INTO list_members(list_id, user_id, role)
+SELECT id, owner_id, 'owner'
+FROM lists
+WHERE is_personal
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE todos
+	ADD COLUMN IF NOT EXISTS list_id UUID REFERENCES lists(id);
+
+UPDATE todos t
+SET list_id = l.id
+FROM lists l
+WHERE l.owner_id = t.user_id
+AND l.is_personal
+AND t.list_id IS NULL;
+
+ALTER TABLE todos
+	ALTER COLUMN list_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS todos_list_id ON todos(list_id);
+
+COMMIT;
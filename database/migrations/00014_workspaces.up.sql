@@ -0,0 +1,107 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS workspaces(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	name TEXT NOT NULL,
+	personal_owner_id UUID REFERENCES users(id),
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_personal_workspace ON workspaces(personal_owner_id) WHERE personal_owner_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS workspace_members(
+	workspace_id UUID NOT NULL REFERENCES workspaces(id),
+	user_id UUID NOT NULL REFERENCES users(id),
+	role TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS workspace_members_user_id ON workspace_members(user_id);
+
+-- existing data moves into a personal workspace of each list owner
+INSERT INTO workspaces(name, personal_owner_id)
+SELECT DISTINCT 'Personal', owner_id
+FROM lists
+ON CONFLICT DO NOTHING;
+
+INSERT INTO workspace_members(workspace_id, user_id, role)
+SELECT id, personal_owner_id, 'owner'
+FROM workspaces
+WHERE personal_owner_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE lists
+	ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id);
+
+UPDATE lists l
+SET workspace_id = w.id
+FROM workspaces w
+WHERE w.personal_owner_id = l.owner_id
+AND l.workspace_id IS NULL;
+
+ALTER TABLE lists
+	ALTER COLUMN workspace_id SET NOT NULL;
+
+-- members of shared lists keep their access by joining the owner's workspace
+INSERT INTO workspace_members(workspace_id, user_id, role)
+SELECT DISTINCT l.workspace_id, m.user_id, 'member'
+FROM list_members m
+JOIN lists l ON l.id = m.list_id
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS unique_personal_list;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_personal_list ON lists(workspace_id, owner_id) WHERE is_personal;
+CREATE INDEX IF NOT EXISTS lists_workspace_id ON lists(workspace_id);
+
+ALTER TABLE todos
+	ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id);
+
+UPDATE todos t
+SET workspace_id = l.workspace_id
+FROM lists l
+WHERE l.id = t.list_id
+AND t.workspace_id IS NULL;
+
+ALTER TABLE todos
+	ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS todos_workspace_id ON todos(workspace_id);
+
+-- the API connects as todo_app, which neither owns the tables nor bypasses
+-- row-level security; jobs that work across tenants connect as todo_worker.
+-- Both are given a login password at startup.
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'todo_app') THEN
+		CREATE ROLE todo_app NOLOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE NOBYPASSRLS;
+	END IF;
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'todo_worker') THEN
+		CREATE ROLE todo_worker NOLOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE BYPASSRLS;
+	END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO todo_app, todo_worker;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO todo_app, todo_worker;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO todo_app, todo_worker;
+REVOKE ALL ON schema_migrations FROM todo_app, todo_worker;
+
+-- tables added by later migrations are granted the same way
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO todo_app, todo_worker;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO todo_app, todo_worker;
+
+-- the application sets app.workspace_id per transaction
+ALTER TABLE lists ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todos ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY lists_tenant_isolation ON lists
+	USING (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID)
+	WITH CHECK (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID);
+
+CREATE POLICY todos_tenant_isolation ON todos
+	USING (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID)
+	WITH CHECK (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE todos
+	ADD COLUMN IF NOT EXISTS assignee_id UUID REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS todos_assignee_id ON todos(assignee_id) WHERE assignee_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS todo_assignments(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	assigned_by UUID NOT NULL REFERENCES users(id),
+	previous_assignee_id UUID REFERENCES users(id),
+	assignee_id UUID REFERENCES users(id),
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS todo_assignments_todo_id ON todo_assignments(todo_id);
+
+COMMIT;
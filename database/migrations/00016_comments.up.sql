@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS comments(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id),
+	body TEXT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	edited_at TIMESTAMP WITH TIME ZONE,
+	archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS comments_todo_id ON comments(todo_id, created_at) WHERE archived_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS comment_mentions(
+	comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id),
+	PRIMARY KEY (comment_id, user_id)
+);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS attachments(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id),
+	file_name TEXT NOT NULL,
+	content_type TEXT NOT NULL,
+	size_bytes BIGINT NOT NULL,
+	storage_key TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS attachments_todo_id ON attachments(todo_id);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS checklist_items(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	complete BOOLEAN NOT NULL DEFAULT FALSE,
+	position INT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS checklist_items_todo_id ON checklist_items(todo_id, position);
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- deleting a parent promotes its children to top-level todos
+ALTER TABLE todos
+	ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES todos(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS todos_parent_id ON todos(parent_id) WHERE parent_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS todo_dependencies(
+	todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	blocked_by_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	created_by UUID NOT NULL REFERENCES users(id),
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	PRIMARY KEY (todo_id, blocked_by_id),
+	CHECK (todo_id <> blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS todo_dependencies_blocked_by_id ON todo_dependencies(blocked_by_id);
+
+COMMIT;
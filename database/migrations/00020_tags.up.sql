@@ -0,0 +1,33 @@
+BEGIN;
+
+-- a tag belongs either to one user or to one list within a workspace
+CREATE TABLE IF NOT EXISTS tags(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	workspace_id UUID NOT NULL REFERENCES workspaces(id),
+	owner_id UUID REFERENCES users(id),
+	list_id UUID REFERENCES lists(id),
+	name TEXT NOT NULL,
+	color TEXT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	CHECK ((owner_id IS NULL) <> (list_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_user_tag ON tags(workspace_id, owner_id, LOWER(name)) WHERE owner_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_list_tag ON tags(list_id, LOWER(name)) WHERE list_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS todo_tags(
+	todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS todo_tags_tag_id ON todo_tags(tag_id);
+
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tags_tenant_isolation ON tags
+	USING (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID)
+	WITH CHECK (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID);
+
+COMMIT;
@@ -0,0 +1,40 @@
+BEGIN;
+
+ALTER TABLE todos
+	ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'none' CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent')),
+	ADD COLUMN IF NOT EXISTS position DOUBLE PRECISION,
+	ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+UPDATE todos t
+SET position = o.rn * 1024,
+    updated_at = t.created_at
+FROM (
+	SELECT id, row_number() OVER (PARTITION BY list_id ORDER BY created_at, id) AS rn
+	FROM todos
+) o
+WHERE o.id = t.id;
+
+ALTER TABLE todos
+	ALTER COLUMN position SET NOT NULL,
+	ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS todos_list_position ON todos(list_id, position);
+
+-- manual reordering is not an edit, so position changes leave updated_at alone
+CREATE OR REPLACE FUNCTION todos_set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+	IF (to_jsonb(NEW) - 'position' - 'updated_at') IS DISTINCT FROM (to_jsonb(OLD) - 'position' - 'updated_at') THEN
+		NEW.updated_at = NOW();
+	ELSE
+		NEW.updated_at = OLD.updated_at;
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS todos_updated_at ON todos;
+CREATE TRIGGER todos_updated_at
+	BEFORE UPDATE ON todos
+	FOR EACH ROW EXECUTE FUNCTION todos_set_updated_at();
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- name, description and priority are the template for occurrences that
+-- have not been generated yet; dtstart is occurrence first_occurrence
+CREATE TABLE IF NOT EXISTS todo_series(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	workspace_id UUID NOT NULL REFERENCES workspaces(id),
+	user_id UUID NOT NULL REFERENCES users(id),
+	rrule TEXT NOT NULL,
+	dtstart TIMESTAMP WITH TIME ZONE NOT NULL,
+	first_occurrence INTEGER NOT NULL DEFAULT 0,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	priority TEXT NOT NULL DEFAULT 'none',
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE todos
+	ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES todo_series(id) ON DELETE SET NULL,
+	ADD COLUMN IF NOT EXISTS occurrence INTEGER;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_series_occurrence ON todos(series_id, occurrence) WHERE series_id IS NOT NULL;
+
+ALTER TABLE todo_series ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY todo_series_tenant_isolation ON todo_series
+	USING (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID)
+	WITH CHECK (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID);
+
+COMMIT;
@@ -0,0 +1,57 @@
+BEGIN;
+
+-- a reminder fires either at remind_at or offset_seconds before the todo
+-- expires, so moving the due date moves offset reminders with it
+CREATE TABLE IF NOT EXISTS reminders(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	workspace_id UUID NOT NULL REFERENCES workspaces(id),
+	todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id),
+	remind_at TIMESTAMP WITH TIME ZONE,
+	offset_seconds INTEGER CHECK (offset_seconds >= 0),
+	channel TEXT NOT NULL DEFAULT 'in_app' CHECK (channel IN ('email', 'webhook', 'in_app')),
+	webhook_url TEXT,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+	attempts INTEGER NOT NULL DEFAULT 0,
+	retry_at TIMESTAMP WITH TIME ZONE,
+	sent_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	CHECK ((remind_at IS NULL) <> (offset_seconds IS NULL)),
+	CHECK ((channel = 'webhook') = (webhook_url IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS reminders_pending ON reminders(todo_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS reminders_user_id ON reminders(user_id);
+
+CREATE TABLE IF NOT EXISTS reminder_deliveries(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	reminder_id UUID NOT NULL REFERENCES reminders(id) ON DELETE CASCADE,
+	attempt INTEGER NOT NULL,
+	channel TEXT NOT NULL,
+	error TEXT,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reminder_deliveries_reminder_id ON reminder_deliveries(reminder_id, attempt);
+
+-- in-app deliveries land here
+CREATE TABLE IF NOT EXISTS notifications(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id),
+	kind TEXT NOT NULL,
+	title TEXT NOT NULL,
+	body TEXT NOT NULL,
+	todo_id UUID REFERENCES todos(id) ON DELETE CASCADE,
+	read_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id ON notifications(user_id, created_at DESC, id DESC);
+
+ALTER TABLE reminders ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY reminders_tenant_isolation ON reminders
+	USING (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID)
+	WITH CHECK (workspace_id = NULLIF(current_setting('app.workspace_id', true), '')::UUID);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS actor_id UUID REFERENCES users(id);
+
+-- keeps the polled unread count an index-only lookup
+CREATE INDEX IF NOT EXISTS notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+COMMIT;
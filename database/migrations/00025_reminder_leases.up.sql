@@ -0,0 +1,12 @@
+BEGIN;
+
+-- a claimed reminder is 'sending' until retry_at; a worker that dies
+-- mid-batch leaves the lease to expire and another worker picks it up
+ALTER TABLE reminders DROP CONSTRAINT IF EXISTS reminders_status_check;
+ALTER TABLE reminders ADD CONSTRAINT reminders_status_check
+	CHECK (status IN ('pending', 'sending', 'sent', 'failed'));
+
+DROP INDEX IF EXISTS reminders_pending;
+CREATE INDEX IF NOT EXISTS reminders_pending ON reminders(todo_id) WHERE status IN ('pending', 'sending');
+
+COMMIT;
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ChangePasswordRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+
+	currentHash, err := dbHelper.GetUserPasswordHash(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch user")
+		return
+	}
+	if err := utils.CheckPassword(currentHash, req.CurrentPassword); err != nil {
+		utils.RespondError(w, http.StatusUnauthorized, nil, "current password is incorrect")
+		return
+	}
+
+	hashPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed while hashing password")
+		return
+	}
+	if err := dbHelper.UpdateUserPassword(userCtx.UserID, hashPassword); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to update password")
+		return
+	}
+	if _, err := dbHelper.RevokeOtherSessions(userCtx.UserID, userCtx.SessionID); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke other sessions")
+		return
+	}
+	if req.RevokeAPIKeys {
+		if _, err := dbHelper.RevokeAllAPIKeys(userCtx.UserID); err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke api keys")
+			return
+		}
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "password changed successfully",
+	})
+}
+
+func ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	var req models.ChangeEmailRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+
+	currentHash, err := dbHelper.GetUserPasswordHash(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch user")
+		return
+	}
+	if err := utils.CheckPassword(currentHash, req.Password); err != nil {
+		utils.RespondError(w, http.StatusUnauthorized, nil, "password is incorrect")
+		return
+	}
+
+	exists, err := dbHelper.IsUserExists(req.Email)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check user existence")
+		return
+	}
+	if exists {
+		utils.RespondError(w, http.StatusConflict, nil, "email already in use")
+		return
+	}
+
+	if err := dbHelper.UpdateUserEmail(userCtx.UserID, req.Email); err != nil {
+		if errors.Is(err, dbHelper.ErrEmailTaken) {
+			utils.RespondError(w, http.StatusConflict, err, "email already in use")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to update email")
+		return
+	}
+	// the change is already committed, so a failed send must not turn it
+	// into an error; the user can ask for another mail via /v1/verify/resend
+	go func() {
+		if err := sendVerificationEmail(userCtx.UserID, req.Email); err != nil {
+			fmt.Printf("failed to send verification email to user %s: %v\n", userCtx.UserID, err)
+		}
+	}()
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "email changed successfully, verify the new address",
+	})
+}
+
+func ChangeTimezone(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateTimezoneRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+	// "Local" would mean the server's zone rather than the user's
+	if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "Local" {
+		utils.RespondError(w, http.StatusBadRequest, err, "timezone must be an IANA name such as Europe/Berlin")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.UpdateUserTimezone(userCtx.UserID, req.Timezone); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to update timezone")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "timezone changed successfully",
+	})
+}
+
+func DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var req models.DeleteAccountRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+
+	currentHash, err := dbHelper.GetUserPasswordHash(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch user")
+		return
+	}
+	if err := utils.CheckPassword(currentHash, req.Password); err != nil {
+		utils.RespondError(w, http.StatusUnauthorized, nil, "password is incorrect")
+		return
+	}
+
+	gracePeriod := utils.GetEnvDuration("ACCOUNT_PURGE_GRACE_PERIOD", utils.DefaultAccountPurgeGracePeriod)
+	if err := dbHelper.DeleteAccount(userCtx.UserID, gracePeriod); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "user not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to delete account")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Message string    `json:"message"`
+		PurgeAt time.Time `json:"purgeAt"`
+	}{
+		Message: "account deleted successfully",
+		PurgeAt: time.Now().Add(gracePeriod),
+	})
+}
+
+func ExportAccount(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "format must be json or zip")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+
+	profile, err := dbHelper.GetUserProfile(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch profile")
+		return
+	}
+	todos, err := dbHelper.GetAllUserTodos(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch todos")
+		return
+	}
+	sessions, err := dbHelper.GetSessionHistory(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch sessions")
+		return
+	}
+
+	export := models.AccountExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    *profile,
+		Todos:      todos,
+		Sessions:   sessions,
+	}
+
+	if format != "zip" {
+		w.Header().Set("Content-Disposition", `attachment; filename="export.json"`)
+		utils.RespondJSON(w, http.StatusOK, export)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="export.zip"`)
+	w.WriteHeader(http.StatusOK)
+
+	archive := zip.NewWriter(w)
+	files := map[string]interface{}{
+		"profile.json":  export.Profile,
+		"todos.json":    export.Todos,
+		"sessions.json": export.Sessions,
+	}
+	for _, name := range []string{"profile.json", "todos.json", "sessions.json"} {
+		f, err := archive.Create(name)
+		if err != nil {
+			fmt.Printf("failed to write export archive: %v\n", err)
+			return
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(files[name]); err != nil {
+			fmt.Printf("failed to write export archive: %v\n", err)
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		fmt.Printf("failed to write export archive: %v\n", err)
+	}
+}
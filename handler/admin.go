@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func AdminListUsers(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := utils.ParseLimitOffset(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid pagination")
+		return
+	}
+	archived := utils.ParseBool(r.URL.Query().Get("archived"))
+
+	users, err := dbHelper.ListUsers(r.URL.Query().Get("search"), archived, limit, offset)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch users")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Users []models.AdminUser `json:"users"`
+	}{
+		Users: users,
+	})
+}
+
+func AdminGetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := dbHelper.GetAdminUser(chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "user not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch user")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, user)
+}
+
+func AdminArchiveUser(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == middleware.UserContext(r).UserID {
+		utils.RespondError(w, http.StatusBadRequest, nil, "cannot archive your own account")
+		return
+	}
+
+	if err := dbHelper.ArchiveUser(userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "active user not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to archive user")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "user archived successfully",
+	})
+}
+
+func AdminUnarchiveUser(w http.ResponseWriter, r *http.Request) {
+	if err := dbHelper.UnarchiveUser(chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "archived user not found")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrEmailTaken) {
+			utils.RespondError(w, http.StatusConflict, err, "another active account uses this email")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrAccountPurged) {
+			utils.RespondError(w, http.StatusGone, err, "account has been purged and cannot be restored")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to unarchive user")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "user unarchived successfully",
+	})
+}
+
+func AdminSetUserRole(w http.ResponseWriter, r *http.Request) {
+	var req models.SetRoleRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userID := chi.URLParam(r, "id")
+	if userID == middleware.UserContext(r).UserID {
+		utils.RespondError(w, http.StatusBadRequest, nil, "cannot change your own role")
+		return
+	}
+
+	if err := dbHelper.SetUserRole(userID, req.Role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "user not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to update role")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "role updated successfully",
+	})
+}
+
+func AdminGetUserSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := dbHelper.GetActiveSessions(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch sessions")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Sessions []models.Session `json:"sessions"`
+	}{
+		Sessions: sessions,
+	})
+}
+
+func AdminRevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	revoked, err := dbHelper.RevokeAllSessions(userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke sessions")
+		return
+	}
+	// API keys authenticate without a session, so a forced logout revokes them as well
+	revokedKeys, err := dbHelper.RevokeAllAPIKeys(userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke api keys")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Message        string `json:"message"`
+		Revoked        int64  `json:"revoked"`
+		RevokedAPIKeys int64  `json:"revokedApiKeys"`
+	}{
+		Message:        "sessions and api keys revoked successfully",
+		Revoked:        revoked,
+		RevokedAPIKeys: revokedKeys,
+	})
+}
+
+func AdminRevokeUserSession(w http.ResponseWriter, r *http.Request) {
+	if err := dbHelper.RevokeSession(chi.URLParam(r, "id"), chi.URLParam(r, "sessionId")); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "session not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke session")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "session revoked successfully",
+	})
+}
+
+func AdminGetLockouts(w http.ResponseWriter, r *http.Request) {
+	lockouts, err := dbHelper.GetActiveLockouts()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch lockouts")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Lockouts []models.LoginThrottle `json:"lockouts"`
+	}{
+		Lockouts: lockouts,
+	})
+}
+
+func AdminClearLockout(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "key is required")
+		return
+	}
+	if err := dbHelper.ClearLoginFailures(key); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to clear lockout")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "lockout cleared successfully",
+	})
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIKeyRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		utils.RespondError(w, http.StatusBadRequest, nil, "expiresAt must be in the future")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+
+	key, err := dbHelper.CreateAPIKey(userCtx.UserID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create api key")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, key)
+}
+
+func GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	keys, err := dbHelper.GetAPIKeys(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch api keys")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		APIKeys []models.APIKey `json:"apiKeys"`
+	}{
+		APIKeys: keys,
+	})
+}
+
+func RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyID := chi.URLParam(r, "id")
+	if keyID == "" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "api key id is required")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.RevokeAPIKey(userCtx.UserID, keyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "api key not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke api key")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "api key revoked successfully",
+	})
+}
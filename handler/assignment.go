@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func AssignTodo(w http.ResponseWriter, r *http.Request) {
+	var req models.AssignTodoRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.AssignTodo(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.AssigneeId)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrInvalidAssignee) {
+			utils.RespondError(w, http.StatusBadRequest, err, "assignee does not have access to this todo")
+			return
+		}
+		respondAccessError(w, err, "failed to assign todo")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "assigned successfully")
+}
+
+func GetTodoAssignments(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	assignments, err := dbHelper.GetTodoAssignments(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondAccessError(w, err, "failed to fetch assignments")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Assignments []models.TodoAssignment `json:"assignments"`
+	}{
+		Assignments: assignments,
+	})
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/nikhilpratapgit/TodoApp/blob"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondAttachmentError(w http.ResponseWriter, err error, messageToUser string) {
+	if errors.Is(err, dbHelper.ErrAttachmentNotFound) {
+		utils.RespondError(w, http.StatusNotFound, err, "attachment not found")
+		return
+	}
+	respondAccessError(w, err, messageToUser)
+}
+
+func attachmentFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+func UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	todoID := chi.URLParam(r, "id")
+
+	if err := dbHelper.RequireTodoWrite(userCtx.UserID, userCtx.WorkspaceID, todoID); err != nil {
+		respondAccessError(w, err, "failed to upload attachment")
+		return
+	}
+
+	maxBytes := int64(utils.GetEnvInt("ATTACHMENT_MAX_BYTES", utils.DefaultAttachmentMaxBytes))
+	// leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1<<20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.RespondError(w, http.StatusRequestEntityTooLarge, err, fmt.Sprintf("attachments are limited to %d bytes", maxBytes))
+			return
+		}
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid multipart body")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "file is required")
+		return
+	}
+	defer file.Close()
+	if header.Size > maxBytes {
+		utils.RespondError(w, http.StatusRequestEntityTooLarge, nil, fmt.Sprintf("attachments are limited to %d bytes", maxBytes))
+		return
+	}
+
+	// the client supplied Content-Type is ignored in favour of the sniffed one
+	detected, err := mimetype.DetectReader(file)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to read file")
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to read file")
+		return
+	}
+
+	attachment := &models.Attachment{
+		ID:          uuid.NewString(),
+		TodoID:      todoID,
+		UserID:      userCtx.UserID,
+		FileName:    attachmentFileName(header.Filename),
+		ContentType: detected.String(),
+		SizeBytes:   header.Size,
+	}
+	attachment.StorageKey = "todos/" + todoID + "/" + attachment.ID
+
+	if err := blob.Default.Put(r.Context(), attachment.StorageKey, file, attachment.SizeBytes, attachment.ContentType); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to store attachment")
+		return
+	}
+	if err := dbHelper.CreateAttachment(userCtx.UserID, userCtx.WorkspaceID, attachment); err != nil {
+		if deleteErr := blob.Default.Delete(context.Background(), attachment.StorageKey); deleteErr != nil {
+			fmt.Printf("failed to delete orphaned attachment %s: %v\n", attachment.StorageKey, deleteErr)
+		}
+		respondAccessError(w, err, "failed to save attachment")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, attachment)
+}
+
+func GetAttachments(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	attachments, err := dbHelper.GetAttachments(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondAccessError(w, err, "failed to fetch attachments")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Attachments []models.Attachment `json:"attachments"`
+	}{
+		Attachments: attachments,
+	})
+}
+
+func GetAttachmentURL(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	attachment, err := dbHelper.GetAttachment(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		respondAttachmentError(w, err, "failed to fetch attachment")
+		return
+	}
+
+	expiresAt := time.Now().Add(utils.GetEnvDuration("ATTACHMENT_URL_TTL", utils.DefaultAttachmentURLTTL))
+	utils.RespondJSON(w, http.StatusOK, models.AttachmentURL{
+		URL:       utils.SignURL("/v1/attachments/"+attachment.ID+"/content", expiresAt),
+		ExpiresAt: expiresAt.UTC().Truncate(time.Second),
+	})
+}
+
+// DownloadAttachment serves a signed link, so it runs without the auth
+// middleware; the signature and expiry are the authorization.
+func DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	if !utils.VerifySignedURL(r) {
+		utils.RespondError(w, http.StatusForbidden, nil, "invalid or expired link")
+		return
+	}
+
+	attachment, err := dbHelper.GetSignedAttachment(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAttachmentError(w, err, "failed to fetch attachment")
+		return
+	}
+	content, err := blob.Default.Get(r.Context(), attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			utils.RespondError(w, http.StatusNotFound, err, "attachment not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to read attachment")
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	if _, err := io.Copy(w, content); err != nil {
+		fmt.Printf("failed to stream attachment %s: %v\n", attachment.ID, err)
+	}
+}
+
+func DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	storageKey, err := dbHelper.DeleteAttachment(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		respondAttachmentError(w, err, "failed to delete attachment")
+		return
+	}
+	if err := blob.Default.Delete(r.Context(), storageKey); err != nil {
+		fmt.Printf("failed to delete attachment blob %s: %v\n", storageKey, err)
+	}
+	utils.RespondJSON(w, http.StatusOK, "attachment deleted successfully")
+}
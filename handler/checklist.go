@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondChecklistError(w http.ResponseWriter, err error, messageToUser string) {
+	switch {
+	case errors.Is(err, dbHelper.ErrChecklistItemNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, "checklist item not found")
+	case errors.Is(err, dbHelper.ErrInvalidChecklistOrder):
+		utils.RespondError(w, http.StatusBadRequest, err, "item ids must list every checklist item exactly once")
+	default:
+		respondAccessError(w, err, messageToUser)
+	}
+}
+
+func GetChecklist(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	items, err := dbHelper.GetChecklist(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondChecklistError(w, err, "failed to fetch checklist")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Items []models.ChecklistItem `json:"items"`
+	}{
+		Items: items,
+	})
+}
+
+func CreateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateChecklistItemRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	item, err := dbHelper.CreateChecklistItem(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.Name)
+	if err != nil {
+		respondChecklistError(w, err, "failed to create checklist item")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, item)
+}
+
+func UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateChecklistItemRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	autoComplete := utils.GetEnvBool("CHECKLIST_AUTO_COMPLETE", false)
+	item, err := dbHelper.UpdateChecklistItem(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "itemId"),
+		req.Name, req.Complete, autoComplete)
+	if err != nil {
+		respondChecklistError(w, err, "failed to update checklist item")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, item)
+}
+
+func DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.DeleteChecklistItem(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "itemId"))
+	if err != nil {
+		respondChecklistError(w, err, "failed to delete checklist item")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "checklist item deleted successfully")
+}
+
+func ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	var req models.ReorderChecklistRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	items, err := dbHelper.ReorderChecklist(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.ItemIDs)
+	if err != nil {
+		respondChecklistError(w, err, "failed to reorder checklist")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Items []models.ChecklistItem `json:"items"`
+	}{
+		Items: items,
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondCommentError(w http.ResponseWriter, err error, messageToUser string) {
+	switch {
+	case errors.Is(err, dbHelper.ErrCommentNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, "comment not found")
+	case errors.Is(err, dbHelper.ErrCommentForbidden):
+		utils.RespondError(w, http.StatusForbidden, err, "only the author can change this comment")
+	default:
+		respondAccessError(w, err, messageToUser)
+	}
+}
+
+func CreateComment(w http.ResponseWriter, r *http.Request) {
+	var req models.CommentRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	comment, err := dbHelper.CreateComment(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.Body)
+	if err != nil {
+		respondCommentError(w, err, "failed to create comment")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, comment)
+}
+
+func GetComments(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := utils.ParseLimitOffset(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid pagination")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	comments, err := dbHelper.GetComments(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), limit, offset)
+	if err != nil {
+		respondCommentError(w, err, "failed to fetch comments")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Comments []models.Comment `json:"comments"`
+	}{
+		Comments: comments,
+	})
+}
+
+func UpdateComment(w http.ResponseWriter, r *http.Request) {
+	var req models.CommentRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	comment, err := dbHelper.UpdateComment(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "commentId"), req.Body)
+	if err != nil {
+		respondCommentError(w, err, "failed to update comment")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, comment)
+}
+
+func DeleteComment(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.DeleteComment(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "commentId"))
+	if err != nil {
+		respondCommentError(w, err, "failed to delete comment")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "comment deleted successfully")
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondHierarchyError(w http.ResponseWriter, err error, messageToUser string) {
+	switch {
+	case errors.Is(err, dbHelper.ErrInvalidParent):
+		utils.RespondError(w, http.StatusBadRequest, err, "parent must be another todo in the same list")
+	case errors.Is(err, dbHelper.ErrTodoCycle):
+		utils.RespondError(w, http.StatusConflict, err, "change would create a cycle")
+	case errors.Is(err, dbHelper.ErrDependencyNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, "dependency not found")
+	default:
+		respondAccessError(w, err, messageToUser)
+	}
+}
+
+func SetTodoParent(w http.ResponseWriter, r *http.Request) {
+	var req models.SetParentRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.SetTodoParent(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.ParentId); err != nil {
+		respondHierarchyError(w, err, "failed to update parent")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "updated successfully")
+}
+
+func GetTodoSubtree(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	tree, err := dbHelper.GetTodoSubtree(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondHierarchyError(w, err, "failed to fetch subtree")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, tree)
+}
+
+func GetTodoDependencies(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	graph, err := dbHelper.GetDependencyGraph(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondHierarchyError(w, err, "failed to fetch dependencies")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, graph)
+}
+
+func AddTodoDependency(w http.ResponseWriter, r *http.Request) {
+	var req models.AddDependencyRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.AddTodoDependency(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.BlockedById); err != nil {
+		respondHierarchyError(w, err, "failed to add dependency")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, "dependency added successfully")
+}
+
+func RemoveTodoDependency(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.RemoveTodoDependency(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "blockedById"))
+	if err != nil {
+		respondHierarchyError(w, err, "failed to remove dependency")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "dependency removed successfully")
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/mailer"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondAccessError(w http.ResponseWriter, err error, messageToUser string) {
+	switch {
+	case errors.Is(err, dbHelper.ErrTodoNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, "todo not found")
+	case errors.Is(err, dbHelper.ErrListNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, "list not found")
+	case errors.Is(err, dbHelper.ErrListForbidden):
+		utils.RespondError(w, http.StatusForbidden, err, "you do not have permission to do this")
+	case errors.Is(err, sql.ErrNoRows):
+		utils.RespondError(w, http.StatusNotFound, err, "not found")
+	default:
+		utils.RespondError(w, http.StatusInternalServerError, err, messageToUser)
+	}
+}
+
+func GetLists(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	if _, err := dbHelper.GetOrCreatePersonalList(userCtx.UserID, userCtx.WorkspaceID); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch lists")
+		return
+	}
+	lists, err := dbHelper.GetLists(userCtx.UserID, userCtx.WorkspaceID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch lists")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Lists []models.List `json:"lists"`
+	}{
+		Lists: lists,
+	})
+}
+
+func CreateList(w http.ResponseWriter, r *http.Request) {
+	var req models.ListRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	list, err := dbHelper.CreateList(userCtx.UserID, userCtx.WorkspaceID, req.Name)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create list")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, list)
+}
+
+func GetListById(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	list, err := dbHelper.GetListByID(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondAccessError(w, err, "failed to fetch list")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, list)
+}
+
+func RenameList(w http.ResponseWriter, r *http.Request) {
+	var req models.ListRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.RenameList(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.Name); err != nil {
+		respondAccessError(w, err, "failed to update list")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "updated successfully")
+}
+
+func DeleteList(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.ArchiveList(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, dbHelper.ErrPersonalListProtected) {
+			utils.RespondError(w, http.StatusBadRequest, err, "personal list cannot be deleted")
+			return
+		}
+		respondAccessError(w, err, "failed to delete list")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "list deleted successfully")
+}
+
+func GetListMembers(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	members, err := dbHelper.GetListMembers(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondAccessError(w, err, "failed to fetch members")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Members []models.ListMember `json:"members"`
+	}{
+		Members: members,
+	})
+}
+
+func UpdateListMember(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateMemberRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.UpdateListMember(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "userId"), req.Role)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrOwnerMembership) {
+			utils.RespondError(w, http.StatusBadRequest, err, "the list owner cannot be changed")
+			return
+		}
+		respondAccessError(w, err, "failed to update member")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "updated successfully")
+}
+
+func RemoveListMember(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.RemoveListMember(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "userId"))
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrOwnerMembership) {
+			utils.RespondError(w, http.StatusBadRequest, err, "the list owner cannot leave the list")
+			return
+		}
+		respondAccessError(w, err, "failed to remove member")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "member removed successfully")
+}
+
+func InviteListMember(w http.ResponseWriter, r *http.Request) {
+	var req models.InviteMemberRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	listID := chi.URLParam(r, "id")
+
+	list, err := dbHelper.GetListByID(userCtx.UserID, userCtx.WorkspaceID, listID)
+	if err != nil {
+		respondAccessError(w, err, "failed to fetch list")
+		return
+	}
+
+	ttl := utils.GetEnvDuration("LIST_INVITATION_TTL", utils.DefaultListInvitationTTL)
+	invitationID, token, err := dbHelper.CreateListInvitation(userCtx.UserID, userCtx.WorkspaceID, listID, req.Email, req.Role, ttl)
+	if err != nil {
+		respondAccessError(w, err, "failed to create invitation")
+		return
+	}
+
+	if err := mailer.Default.Send(mailer.Message{
+		To:      req.Email,
+		Subject: fmt.Sprintf("You have been invited to %q", list.Name),
+		Body: fmt.Sprintf("You have been invited to the list %q as %s. The invitation expires in %s.\n\nAccept it from the app with this token:\n\n%s",
+			list.Name, req.Role, ttl, token),
+	}); err != nil {
+		// an invitation nobody was told about would stay acceptable until it expires
+		if revokeErr := dbHelper.RevokeListInvitation(userCtx.UserID, userCtx.WorkspaceID, listID, invitationID); revokeErr != nil {
+			fmt.Printf("failed to revoke unsent invitation %s: %v\n", invitationID, revokeErr)
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to send invitation email")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]string{
+		"message": "invitation sent successfully",
+	})
+}
+
+func GetListInvitations(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	invitations, err := dbHelper.GetListInvitations(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondAccessError(w, err, "failed to fetch invitations")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Invitations []models.ListInvitation `json:"invitations"`
+	}{
+		Invitations: invitations,
+	})
+}
+
+func RevokeListInvitation(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.RevokeListInvitation(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "invitationId"))
+	if err != nil {
+		respondAccessError(w, err, "failed to revoke invitation")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "invitation revoked successfully")
+}
+
+func AcceptListInvitation(w http.ResponseWriter, r *http.Request) {
+	var req models.AcceptInvitationRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	listID, workspaceID, err := dbHelper.AcceptListInvitation(userCtx.UserID, req.Token)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrInvalidInvitation) {
+			utils.RespondError(w, http.StatusBadRequest, err, "invalid or expired invitation")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrInvitationEmail) {
+			utils.RespondError(w, http.StatusForbidden, err, "invitation was sent to a different email address")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to accept invitation")
+		return
+	}
+	list, err := dbHelper.GetListByID(userCtx.UserID, workspaceID, listID)
+	if err != nil {
+		respondAccessError(w, err, "failed to fetch list")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondNotificationError(w http.ResponseWriter, err error, messageToUser string) {
+	if errors.Is(err, dbHelper.ErrNotificationNotFound) {
+		utils.RespondError(w, http.StatusNotFound, err, "notification not found")
+		return
+	}
+	utils.RespondError(w, http.StatusInternalServerError, err, messageToUser)
+}
+
+// GetNotifications pages with ?cursor= taken from the previous page's
+// nextCursor; ?unread=true hides notifications that were read.
+func GetNotifications(w http.ResponseWriter, r *http.Request) {
+	limit, _, err := utils.ParseLimitOffset(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	page, err := dbHelper.GetNotifications(userCtx.UserID, utils.ParseBool(r.URL.Query().Get("unread")), r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidCursor) {
+			utils.RespondError(w, http.StatusBadRequest, err, "invalid cursor")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch notifications")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, page)
+}
+
+func GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	count, err := dbHelper.GetUnreadNotificationCount(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to count notifications")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Unread int `json:"unread"`
+	}{
+		Unread: count,
+	})
+}
+
+func MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.MarkNotificationRead(userCtx.UserID, chi.URLParam(r, "id")); err != nil {
+		respondNotificationError(w, err, "failed to mark notification as read")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "marked as read")
+}
+
+func MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	marked, err := dbHelper.MarkAllNotificationsRead(userCtx.UserID)
+	if err != nil {
+		respondNotificationError(w, err, "failed to mark notifications as read")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Marked int64 `json:"marked"`
+	}{
+		Marked: marked,
+	})
+}
+
+func DeleteNotification(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.DeleteNotification(userCtx.UserID, chi.URLParam(r, "id")); err != nil {
+		respondNotificationError(w, err, "failed to delete notification")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "notification deleted successfully")
+}
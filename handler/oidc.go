@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/oidc"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+// oidcStateCookie binds a login state to the browser that started it.
+const oidcStateCookie = "oidc_state"
+
+func setOIDCStateCookie(w http.ResponseWriter, providerName, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     "/v1/oidc/" + providerName,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(utils.AppBaseURL(), "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+	provider, ok := oidc.Providers[providerName]
+	if !ok {
+		utils.RespondError(w, http.StatusNotFound, nil, "unknown identity provider")
+		return
+	}
+
+	state, err := utils.GenerateRandomToken()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to start login")
+		return
+	}
+	nonce, err := utils.GenerateRandomToken()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to start login")
+		return
+	}
+	codeVerifier, err := utils.GenerateRandomToken()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to start login")
+		return
+	}
+
+	if err := dbHelper.CreateOIDCLoginState(providerName, state, nonce, codeVerifier, utils.DefaultOIDCStateTTL); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to start login")
+		return
+	}
+
+	authURL, err := provider.AuthCodeURL(r.Context(), state, nonce, oidc.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadGateway, err, "identity provider is unavailable")
+		return
+	}
+	setOIDCStateCookie(w, providerName, utils.HashToken(state), int(utils.DefaultOIDCStateTTL.Seconds()))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+	provider, ok := oidc.Providers[providerName]
+	if !ok {
+		utils.RespondError(w, http.StatusNotFound, nil, "unknown identity provider")
+		return
+	}
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		utils.RespondError(w, http.StatusUnauthorized, errors.New(providerErr), "identity provider denied the login")
+		return
+	}
+	code, state := query.Get("code"), query.Get("state")
+	if code == "" || state == "" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "code and state are required")
+		return
+	}
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(utils.HashToken(state))) != 1 {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid or expired login state")
+		return
+	}
+	setOIDCStateCookie(w, providerName, "", -1)
+
+	loginState, err := dbHelper.ConsumeOIDCLoginState(providerName, state)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrInvalidOIDCState) {
+			utils.RespondError(w, http.StatusBadRequest, err, "invalid or expired login state")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to complete login")
+		return
+	}
+
+	tokens, err := provider.Exchange(r.Context(), code, loginState.CodeVerifier)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadGateway, err, "failed to exchange authorization code")
+		return
+	}
+	claims, err := provider.VerifyIDToken(r.Context(), tokens.IDToken, loginState.Nonce)
+	if err != nil {
+		utils.RespondError(w, http.StatusUnauthorized, err, "invalid id token")
+		return
+	}
+
+	userID, err := dbHelper.ResolveIdentity(providerName, models.ExternalIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	})
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrEmailTaken) {
+			utils.RespondError(w, http.StatusConflict, err, "an account with this email already exists, log in with your password")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrAccountDisabled) {
+			utils.RespondError(w, http.StatusForbidden, err, "account disabled")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to link identity")
+		return
+	}
+	completeLogin(w, r, userID)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/oidc"
+	"github.com/nikhilpratapgit/TodoApp/oidc/oidctest"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+// The callback must reject a state that was not started in this browser
+// before it touches the stored login state, so these cases need no database.
+func TestOIDCCallbackRequiresStateCookie(t *testing.T) {
+	server, err := oidctest.NewServer("todo-app")
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer server.Close()
+
+	previous := oidc.Providers
+	oidc.Providers = map[string]*oidc.Provider{"oidctest": server.Provider("http://app.test/v1/oidc/oidctest/callback")}
+	defer func() { oidc.Providers = previous }()
+
+	router := chi.NewRouter()
+	router.Get("/v1/oidc/{provider}/callback", OIDCCallback)
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+	}{
+		{name: "no cookie"},
+		{name: "cookie of another login", cookie: &http.Cookie{Name: oidcStateCookie, Value: utils.HashToken("other-state")}},
+		{name: "raw state instead of its hash", cookie: &http.Cookie{Name: oidcStateCookie, Value: "attacker-state"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/oidc/oidctest/callback?code=some-code&state=attacker-state", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/mailer"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	// the response is identical whether or not the account exists
+	response := map[string]string{
+		"message": "if the account exists, a password reset email has been sent",
+	}
+
+	userID, err := dbHelper.GetUserIDByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondJSON(w, http.StatusOK, response)
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to process request")
+		return
+	}
+
+	// so is the response time: the token and mail are handled after
+	// responding, otherwise the slow send would give existing accounts away
+	go sendPasswordReset(userID, req.Email)
+	utils.RespondJSON(w, http.StatusOK, response)
+}
+func sendPasswordReset(userID, email string) {
+	ttl := utils.GetEnvDuration("PASSWORD_RESET_TTL", utils.DefaultPasswordResetTTL)
+	token, err := dbHelper.CreatePasswordResetToken(userID, ttl)
+	if err != nil {
+		fmt.Printf("failed to create reset token for user %s: %v\n", userID, err)
+		return
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", utils.AppBaseURL(), url.QueryEscape(token))
+	if err := mailer.Default.Send(mailer.Message{
+		To:      email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use the link below to reset your password. It expires in %s.\n\n%s\n\nReset token: %s",
+			ttl, link, token),
+	}); err != nil {
+		fmt.Printf("failed to send reset email to user %s: %v\n", userID, err)
+	}
+}
+
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	hashPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed while hashing password")
+		return
+	}
+
+	if err := dbHelper.ResetPassword(req.Token, hashPassword); err != nil {
+		if errors.Is(err, dbHelper.ErrInvalidResetToken) {
+			utils.RespondError(w, http.StatusBadRequest, err, "invalid or expired reset token")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to reset password")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "password reset successfully",
+	})
+}
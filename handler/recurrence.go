@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/recurrence"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondRecurrenceError(w http.ResponseWriter, err error, messageToUser string) {
+	switch {
+	case errors.Is(err, recurrence.ErrInvalidRule):
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid recurrence rule")
+	case errors.Is(err, dbHelper.ErrNotRecurring):
+		utils.RespondError(w, http.StatusNotFound, err, "todo is not recurring")
+	case errors.Is(err, dbHelper.ErrTodoBlocked):
+		utils.RespondError(w, http.StatusConflict, err, "todo cannot be completed while it has open blockers")
+	default:
+		respondAccessError(w, err, messageToUser)
+	}
+}
+
+func GetTodoRecurrence(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	series, err := dbHelper.GetTodoRecurrence(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondRecurrenceError(w, err, "failed to fetch recurrence")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, series)
+}
+
+func SetTodoRecurrence(w http.ResponseWriter, r *http.Request) {
+	var req models.SetRecurrenceRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	series, err := dbHelper.SetTodoRecurrence(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.RRule)
+	if err != nil {
+		respondRecurrenceError(w, err, "failed to update recurrence")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, series)
+}
+
+func StopTodoRecurrence(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.StopTodoRecurrence(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id")); err != nil {
+		respondRecurrenceError(w, err, "failed to stop recurrence")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "recurrence stopped successfully")
+}
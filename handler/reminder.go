@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondReminderError(w http.ResponseWriter, err error, messageToUser string) {
+	if errors.Is(err, dbHelper.ErrReminderNotFound) {
+		utils.RespondError(w, http.StatusNotFound, err, "reminder not found")
+		return
+	}
+	respondAccessError(w, err, messageToUser)
+}
+
+func GetReminders(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	reminders, err := dbHelper.GetReminders(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondReminderError(w, err, "failed to fetch reminders")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Reminders []models.Reminder `json:"reminders"`
+	}{
+		Reminders: reminders,
+	})
+}
+
+func CreateReminder(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateReminderRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+	if req.WebhookURL != "" && req.Channel != models.ReminderChannelWebhook {
+		utils.RespondError(w, http.StatusBadRequest, nil, "webhookUrl is only used with the webhook channel")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	reminder, err := dbHelper.CreateReminder(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req)
+	if err != nil {
+		respondReminderError(w, err, "failed to create reminder")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, reminder)
+}
+
+func DeleteReminder(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.DeleteReminder(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "reminderId"))
+	if err != nil {
+		respondReminderError(w, err, "failed to delete reminder")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "reminder deleted successfully")
+}
+
+func GetReminderDeliveries(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	deliveries, err := dbHelper.GetReminderDeliveries(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "reminderId"))
+	if err != nil {
+		respondReminderError(w, err, "failed to fetch deliveries")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Deliveries []models.ReminderDelivery `json:"deliveries"`
+	}{
+		Deliveries: deliveries,
+	})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func GetSessions(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	sessions, err := dbHelper.GetActiveSessions(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch sessions")
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == userCtx.SessionID
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Sessions []models.Session `json:"sessions"`
+	}{
+		Sessions: sessions,
+	})
+}
+
+func GetSessionById(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	if sessionID == "" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "session id is required")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	session, err := dbHelper.GetSessionByID(userCtx.UserID, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "session not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch session")
+		return
+	}
+	session.Current = session.ID == userCtx.SessionID
+	utils.RespondJSON(w, http.StatusOK, session)
+}
+
+func RevokeSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	if sessionID == "" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "session id is required")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.RevokeSession(userCtx.UserID, sessionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "session not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke session")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "session revoked successfully",
+	})
+}
+
+func RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	revoked, err := dbHelper.RevokeOtherSessions(userCtx.UserID, userCtx.SessionID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke sessions")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Message string `json:"message"`
+		Revoked int64  `json:"revoked"`
+	}{
+		Message: "other sessions revoked successfully",
+		Revoked: revoked,
+	})
+}
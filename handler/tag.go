@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondTagError(w http.ResponseWriter, err error, messageToUser string) {
+	switch {
+	case errors.Is(err, dbHelper.ErrTagNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, "tag not found")
+	case errors.Is(err, dbHelper.ErrTagExists):
+		utils.RespondError(w, http.StatusConflict, err, "a tag with this name already exists")
+	case errors.Is(err, dbHelper.ErrInvalidTag):
+		utils.RespondError(w, http.StatusBadRequest, err, "tag belongs to a different list")
+	default:
+		respondAccessError(w, err, messageToUser)
+	}
+}
+
+func GetTags(w http.ResponseWriter, r *http.Request) {
+	listID := r.URL.Query().Get("listId")
+	if listID != "" {
+		if err := utils.Validate.Var(listID, "uuid"); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err, "invalid list id")
+			return
+		}
+	}
+
+	userCtx := middleware.UserContext(r)
+	tags, err := dbHelper.GetTags(userCtx.UserID, userCtx.WorkspaceID, listID)
+	if err != nil {
+		respondTagError(w, err, "failed to fetch tags")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Tags []models.Tag `json:"tags"`
+	}{
+		Tags: tags,
+	})
+}
+
+func CreateTag(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateTagRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	tag, err := dbHelper.CreateTag(userCtx.UserID, userCtx.WorkspaceID, req)
+	if err != nil {
+		respondTagError(w, err, "failed to create tag")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, tag)
+}
+
+func UpdateTag(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateTagRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	tag, err := dbHelper.UpdateTag(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.Name, req.Color)
+	if err != nil {
+		respondTagError(w, err, "failed to update tag")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, tag)
+}
+
+func DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	if err := dbHelper.DeleteTag(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id")); err != nil {
+		respondTagError(w, err, "failed to delete tag")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "tag deleted successfully")
+}
+
+func AddTodoTag(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.AddTodoTag(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "tagId"))
+	if err != nil {
+		respondTagError(w, err, "failed to tag todo")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "tag added successfully")
+}
+
+func RemoveTodoTag(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.RemoveTodoTag(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), chi.URLParam(r, "tagId"))
+	if err != nil {
+		respondTagError(w, err, "failed to remove tag")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "tag removed successfully")
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	rotation, err := dbHelper.RotateRefreshToken(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrRefreshTokenReused) {
+			utils.RespondError(w, http.StatusUnauthorized, err, "refresh token reuse detected, session revoked")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrInvalidRefreshToken) {
+			utils.RespondError(w, http.StatusUnauthorized, err, "invalid refresh token")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to refresh token")
+		return
+	}
+
+	token, err := utils.GenerateJWT(rotation.UserID, rotation.SessionID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to generate token")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, models.TokenPair{
+		Token:        token,
+		RefreshToken: rotation.RefreshToken,
+	})
+}
+
+func issueSession(w http.ResponseWriter, r *http.Request, userID string) (*models.TokenPair, bool) {
+	sessionID, sessionErr := dbHelper.CreateUserSession(userID, r.UserAgent(), utils.ClientIP(r))
+	if sessionErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, sessionErr, "failed to create user session")
+		return nil, false
+	}
+	token, err := utils.GenerateJWT(userID, sessionID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to generate token")
+		return nil, false
+	}
+	refreshToken, err := dbHelper.CreateRefreshToken(sessionID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to generate refresh token")
+		return nil, false
+	}
+	return &models.TokenPair{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, true
+}
+
+// completeLogin runs the checks shared by every first-factor login and then
+// either issues a session or hands out a two-factor challenge. throttleKeys
+// are only cleared once a session is issued.
+func completeLogin(w http.ResponseWriter, r *http.Request, userID string, throttleKeys ...string) {
+	if utils.RequireEmailVerification() {
+		verified, err := dbHelper.IsUserVerified(userID)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to check email verification")
+			return
+		}
+		if !verified {
+			utils.RespondError(w, http.StatusForbidden, nil, "email address is not verified")
+			return
+		}
+	}
+
+	enabled, err := dbHelper.IsTwoFactorEnabled(userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check two-factor status")
+		return
+	}
+	if enabled {
+		challenge, err := dbHelper.CreateLoginChallenge(userID, utils.DefaultLoginChallengeTTL)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to create login challenge")
+			return
+		}
+		utils.RespondJSON(w, http.StatusOK, models.LoginChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		})
+		return
+	}
+
+	tokens, ok := issueSession(w, r, userID)
+	if !ok {
+		return
+	}
+	clearLoginFailures(throttleKeys...)
+	utils.RespondJSON(w, http.StatusCreated, tokens)
+}
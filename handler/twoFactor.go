@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/totp"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	state, err := dbHelper.GetTwoFactorState(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch user")
+		return
+	}
+	if state.EnabledAt != nil {
+		utils.RespondError(w, http.StatusConflict, nil, "two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to generate secret")
+		return
+	}
+	if err := dbHelper.SetPendingTOTPSecret(userCtx.UserID, secret); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to save secret")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, models.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(utils.GetEnv("TOTP_ISSUER", "TodoApp"), state.Email, secret),
+	})
+}
+
+func ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req models.TwoFactorCodeRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+
+	recoveryCodes, err := dbHelper.EnableTwoFactor(userCtx.UserID, req.Code)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrTwoFactorNotPending) {
+			utils.RespondError(w, http.StatusBadRequest, err, "start two-factor setup first")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrInvalidTwoFactor) {
+			utils.RespondError(w, http.StatusBadRequest, err, "invalid code")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to enable two-factor authentication")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Message       string   `json:"message"`
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}{
+		Message:       "two-factor authentication enabled",
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+func DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req models.DisableTwoFactorRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+
+	currentHash, err := dbHelper.GetUserPasswordHash(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch user")
+		return
+	}
+	if err := utils.CheckPassword(currentHash, req.Password); err != nil {
+		utils.RespondError(w, http.StatusUnauthorized, nil, "password is incorrect")
+		return
+	}
+
+	if err := dbHelper.DisableTwoFactor(userCtx.UserID, req.Code); err != nil {
+		if errors.Is(err, dbHelper.ErrTwoFactorNotEnabled) {
+			utils.RespondError(w, http.StatusBadRequest, err, "two-factor authentication is not enabled")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrInvalidTwoFactor) {
+			utils.RespondError(w, http.StatusUnauthorized, err, "invalid code")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to disable two-factor authentication")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "two-factor authentication disabled",
+	})
+}
+
+func LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginTwoFactorRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	challengeUserID, email, err := dbHelper.GetLoginChallengeUser(req.ChallengeToken)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrInvalidChallenge) {
+			utils.RespondError(w, http.StatusUnauthorized, err, "invalid or expired login challenge")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to complete login")
+		return
+	}
+
+	// failures count per user across challenges, so a fresh challenge does not reset them
+	userKey := dbHelper.TwoFactorThrottleKey(challengeUserID)
+	ipKey := dbHelper.IPThrottleKey(utils.ClientIP(r))
+	if loginLocked(w, userKey, ipKey) {
+		return
+	}
+
+	userID, err := dbHelper.CompleteLoginChallenge(req.ChallengeToken, req.Code)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrInvalidChallenge) {
+			utils.RespondError(w, http.StatusUnauthorized, err, "invalid or expired login challenge")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrInvalidTwoFactor) {
+			recordLoginFailures(userKey, ipKey)
+			utils.RespondError(w, http.StatusUnauthorized, err, "invalid code")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to complete login")
+		return
+	}
+
+	tokens, ok := issueSession(w, r, userID)
+	if !ok {
+		return
+	}
+	clearLoginFailures(userKey, dbHelper.EmailThrottleKey(email))
+	utils.RespondJSON(w, http.StatusCreated, tokens)
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
-	"database/sql"
 	"errors"
-	//"fmt"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/blob"
 	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
 	"github.com/nikhilpratapgit/TodoApp/middleware"
 	"github.com/nikhilpratapgit/TodoApp/models"
@@ -49,18 +52,32 @@ func RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionID, sessionErr := dbHelper.CreateUserSession(userID)
-	if sessionErr != nil {
-		utils.RespondError(w, http.StatusInternalServerError, sessionErr, "failed to create user session")
+	// the account exists either way, a lost mail can be sent again through /v1/verify/resend
+	message := "user created successfully, verify your email to log in"
+	if err := sendVerificationEmail(userID, registerUser.Email); err != nil {
+		fmt.Printf("failed to send verification email to user %s: %v\n", userID, err)
+		message = "user created successfully, but the verification email could not be sent, request a new one to log in"
+	}
+	if utils.RequireEmailVerification() {
+		utils.RespondJSON(w, http.StatusCreated, map[string]string{
+			"message": message,
+		})
+		return
+	}
+
+	tokens, ok := issueSession(w, r, userID)
+	if !ok {
 		return
 	}
 
 	utils.RespondJSON(w, http.StatusCreated, struct {
-		Message string `json:"message"`
-		Token   string `json:"token"`
+		Message      string `json:"message"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
 	}{
-		Message: "user created successfully",
-		Token:   sessionID,
+		Message:      "user created successfully",
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
 	})
 }
 
@@ -76,27 +93,54 @@ func LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, userErr := dbHelper.GetUserByEmail(req.Email, req.Password)
-	if userErr != nil {
-		utils.RespondError(w, http.StatusUnauthorized, userErr, "failed to find user")
+	emailKey := dbHelper.EmailThrottleKey(req.Email)
+	ipKey := dbHelper.IPThrottleKey(utils.ClientIP(r))
+
+	if loginLocked(w, emailKey, ipKey) {
 		return
 	}
 
-	sessionID, sessionErr := dbHelper.CreateUserSession(userID)
-	if sessionErr != nil {
-		utils.RespondError(w, http.StatusInternalServerError, sessionErr, "failed to create user session")
+	userID, userErr := dbHelper.GetUserByEmail(req.Email, req.Password)
+	if userErr != nil {
+		if !errors.Is(userErr, dbHelper.ErrInvalidCredentials) {
+			utils.RespondError(w, http.StatusInternalServerError, userErr, "failed to log in")
+			return
+		}
+		recordLoginFailures(emailKey, ipKey)
+		utils.RespondError(w, http.StatusUnauthorized, userErr, "invalid email or password")
 		return
 	}
-	token, err := utils.GenerateJWT(userID, sessionID)
+	completeLogin(w, r, userID, emailKey)
+}
+
+// loginLocked responds with 429 when any of keys is locked out.
+func loginLocked(w http.ResponseWriter, keys ...string) bool {
+	lockedUntil, err := dbHelper.GetLoginLockedUntil(keys...)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to generate token")
-		return
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check login attempts")
+		return true
+	}
+	if lockedUntil != nil {
+		retryAfter := int(math.Ceil(time.Until(*lockedUntil).Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		utils.RespondError(w, http.StatusTooManyRequests, nil, "too many login attempts, try again later")
+		return true
+	}
+	return false
+}
+func recordLoginFailures(keys ...string) {
+	for _, key := range keys {
+		if err := dbHelper.RecordLoginFailure(key); err != nil {
+			fmt.Printf("failed to record login failure: %v\n", err)
+		}
+	}
+}
+func clearLoginFailures(keys ...string) {
+	for _, key := range keys {
+		if err := dbHelper.ClearLoginFailures(key); err != nil {
+			fmt.Printf("failed to clear login failures: %v\n", err)
+		}
 	}
-	utils.RespondJSON(w, http.StatusCreated, struct {
-		Token string `json:"token"`
-	}{
-		Token: token,
-	})
 }
 
 func Logout(w http.ResponseWriter, r *http.Request) {
@@ -133,9 +177,35 @@ func CreateTodo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	todo, err := dbHelper.CreateTodo(userID, todoRequest.Name, todoRequest.Description, todoRequest.ExpiringAt)
+	// subtasks default to the list of their parent
+	if todoRequest.ListId == "" && todoRequest.ParentId != "" {
+		parent, err := dbHelper.GetTodoByID(todoRequest.ParentId, userID, userCtx.WorkspaceID)
+		if err != nil {
+			respondAccessError(w, err, "failed to resolve parent todo")
+			return
+		}
+		todoRequest.ListId = parent.ListId
+	}
+	if todoRequest.ListId == "" {
+		personalListID, err := dbHelper.GetOrCreatePersonalList(userID, userCtx.WorkspaceID)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to resolve list")
+			return
+		}
+		todoRequest.ListId = personalListID
+	}
+
+	todo, err := dbHelper.CreateTodo(userID, userCtx.WorkspaceID, todoRequest)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create todo")
+		if errors.Is(err, dbHelper.ErrInvalidAssignee) {
+			utils.RespondError(w, http.StatusBadRequest, err, "assignee does not have access to this list")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrInvalidParent) {
+			utils.RespondError(w, http.StatusBadRequest, err, "parent must be another todo in the same list")
+			return
+		}
+		respondAccessError(w, err, "failed to create todo")
 		return
 	}
 	utils.RespondJSON(w, http.StatusCreated, todo)
@@ -144,10 +214,21 @@ func GetAllTodos(w http.ResponseWriter, r *http.Request) {
 	completeStr := r.URL.Query().Get("status")
 	expiringAtStr := r.URL.Query().Get("expiringAt")
 	search := r.URL.Query().Get("search")
+	listID := r.URL.Query().Get("listId")
+	assigneeID := r.URL.Query().Get("assignee")
 
 	userCtx := middleware.UserContext(r)
 	userID := userCtx.UserID
 
+	if assigneeID == "me" {
+		assigneeID = userID
+	} else if assigneeID != "" {
+		if err := utils.Validate.Var(assigneeID, "uuid"); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err, "assignee must be \"me\" or a user id")
+			return
+		}
+	}
+
 	//model make complete to string
 	var complete bool
 	complete = utils.ParseBool(completeStr)
@@ -155,9 +236,47 @@ func GetAllTodos(w http.ResponseWriter, r *http.Request) {
 	expiringAt, err := utils.ParseExpiringAt(expiringAtStr)
 	if err != nil {
 		utils.RespondError(w, http.StatusBadRequest, err, "invalid time")
+		return
+	}
+	if listID != "" {
+		if _, err := dbHelper.GetListRole(userID, userCtx.WorkspaceID, listID); err != nil {
+			respondAccessError(w, err, "failed to fetch todos")
+			return
+		}
+	}
+
+	// tag=a&tag=b or tag=a,b; tagMode=or matches any of them instead of all
+	var tags []string
+	for _, value := range r.URL.Query()["tag"] {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	tagMode := r.URL.Query().Get("tagMode")
+	if tagMode != "" && tagMode != "and" && tagMode != "or" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "tagMode must be \"and\" or \"or\"")
+		return
 	}
 
-	todos, err := dbHelper.GetTodos(userID, search, expiringAt, complete)
+	// sort=-priority,expiring_at sorts by priority descending, then due date
+	sort, err := dbHelper.ParseTodoSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "sort must use priority, expiring_at, created_at, name, updated_at or position")
+		return
+	}
+
+	todos, err := dbHelper.GetTodos(userID, userCtx.WorkspaceID, models.TodoFilter{
+		ListID:      listID,
+		AssigneeID:  assigneeID,
+		Search:      search,
+		ExpiringAt:  expiringAt,
+		Complete:    complete,
+		Tags:        tags,
+		MatchAnyTag: tagMode == "or",
+		Sort:        sort,
+	})
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "Failed to fetch todos")
 		return
@@ -178,9 +297,9 @@ func GetTodoById(w http.ResponseWriter, r *http.Request) {
 
 	userCtx := middleware.UserContext(r)
 	userID := userCtx.UserID
-	todo, err := dbHelper.GetTodoByID(todoID, userID)
+	todo, err := dbHelper.GetTodoByID(todoID, userID, userCtx.WorkspaceID)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch todo")
+		respondAccessError(w, err, "failed to fetch todo")
 		return
 	}
 	utils.RespondJSON(w, http.StatusOK, todo)
@@ -196,15 +315,16 @@ func DeleteTodoById(w http.ResponseWriter, r *http.Request) {
 	userCtx := middleware.UserContext(r)
 	userID := userCtx.UserID
 
-	err := dbHelper.DeleteTodoById(userID, todoID)
+	storageKeys, err := dbHelper.DeleteTodoById(userID, userCtx.WorkspaceID, todoID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			utils.RespondError(w, http.StatusNotFound, err, "todo not found")
-			return
-		}
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to delete todo")
+		respondAccessError(w, err, "failed to delete todo")
 		return
 	}
+	for _, key := range storageKeys {
+		if err := blob.Default.Delete(r.Context(), key); err != nil {
+			fmt.Printf("failed to delete attachment blob %s: %v\n", key, err)
+		}
+	}
 	utils.RespondJSON(w, http.StatusOK, "todo deleted successfully")
 }
 func UpdateTodoById(w http.ResponseWriter, r *http.Request) {
@@ -227,17 +347,48 @@ func UpdateTodoById(w http.ResponseWriter, r *http.Request) {
 	userCtx := middleware.UserContext(r)
 	userID := userCtx.UserID
 
-	err := dbHelper.UpdateTodoById(todo.Name, todo.Description, todo.Complete, todo.ExpiringAt, todoID, userID)
+	update := dbHelper.UpdateTodoById
+	if todo.Scope == models.ScopeFuture {
+		update = dbHelper.UpdateTodoSeries
+	}
+	err := update(todo.Name, todo.Description, todo.Complete, todo.Priority, todo.ExpiringAt, todoID, userID, userCtx.WorkspaceID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			utils.RespondError(w, http.StatusNotFound, err, "todo not found")
+		if errors.Is(err, dbHelper.ErrNotRecurring) {
+			utils.RespondError(w, http.StatusBadRequest, err, "scope future only applies to recurring todos")
+			return
+		}
+		if errors.Is(err, dbHelper.ErrTodoBlocked) {
+			utils.RespondError(w, http.StatusConflict, err, "todo cannot be completed while it has open blockers")
 			return
 		}
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to update todo")
+		respondAccessError(w, err, "failed to update todo")
 		return
 	}
 	utils.RespondJSON(w, http.StatusOK, "updated successfully")
 }
+func MoveTodo(w http.ResponseWriter, r *http.Request) {
+	var req models.MoveTodoRequest
+	if err := utils.ParseBody(r.Body, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	todo, err := dbHelper.MoveTodo(userCtx.UserID, userCtx.WorkspaceID, chi.URLParam(r, "id"), req.AfterId)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrInvalidPosition) {
+			utils.RespondError(w, http.StatusBadRequest, err, "todo can only be placed after another todo in the same list")
+			return
+		}
+		respondAccessError(w, err, "failed to move todo")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, todo)
+}
 
 //func CompleteTodo(w http.ResponseWriter, r *http.Request) {
 //	userCtx := middleware.UserContext(r)
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+func respondWorkspaceError(w http.ResponseWriter, err error, messageToUser string) {
+	switch {
+	case errors.Is(err, dbHelper.ErrWorkspaceNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, "workspace not found")
+	case errors.Is(err, dbHelper.ErrWorkspaceForbidden):
+		utils.RespondError(w, http.StatusForbidden, err, "you do not have permission to do this")
+	default:
+		respondAccessError(w, err, messageToUser)
+	}
+}
+
+func GetWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	if _, err := dbHelper.GetOrCreatePersonalWorkspace(userCtx.UserID); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch workspaces")
+		return
+	}
+	workspaces, err := dbHelper.GetWorkspaces(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch workspaces")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Workspaces []models.Workspace `json:"workspaces"`
+	}{
+		Workspaces: workspaces,
+	})
+}
+
+func CreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	var req models.WorkspaceRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	workspace, err := dbHelper.CreateWorkspace(userCtx.UserID, req.Name)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create workspace")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, workspace)
+}
+
+func GetWorkspaceMembers(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	members, err := dbHelper.GetWorkspaceMembers(userCtx.UserID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondWorkspaceError(w, err, "failed to fetch members")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Members []models.WorkspaceMember `json:"members"`
+	}{
+		Members: members,
+	})
+}
+
+func AddWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	var req models.AddWorkspaceMemberRequest
+
+	if parseErr := utils.ParseBody(r.Body, &req); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "invalid request body")
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "validation failed")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.AddWorkspaceMember(userCtx.UserID, chi.URLParam(r, "id"), req.Email, req.Role)
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrMemberNotFound) {
+			utils.RespondError(w, http.StatusNotFound, err, "no active user with this email")
+			return
+		}
+		respondWorkspaceError(w, err, "failed to add member")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "member added successfully")
+}
+
+func RemoveWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	err := dbHelper.RemoveWorkspaceMember(userCtx.UserID, chi.URLParam(r, "id"), chi.URLParam(r, "userId"))
+	if err != nil {
+		if errors.Is(err, dbHelper.ErrWorkspaceOwner) {
+			utils.RespondError(w, http.StatusBadRequest, err, "the workspace owner cannot be removed")
+			return
+		}
+		respondWorkspaceError(w, err, "failed to remove member")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "member removed successfully")
+}
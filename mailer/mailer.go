@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+var Default Mailer = NewLogMailer()
+
+// FromEnv has no default driver: the log mailer prints reset and
+// verification links, so it has to be chosen on purpose.
+func FromEnv() (Mailer, error) {
+	switch driver := os.Getenv("MAILER"); driver {
+	case "":
+		return nil, errors.New("MAILER must be set to log or file")
+	case "log":
+		return NewLogMailer(), nil
+	case "file":
+		dir := os.Getenv("MAILER_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return NewFileMailer(dir)
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", driver)
+	}
+}
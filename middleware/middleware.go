@@ -2,11 +2,15 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
 	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
 )
 
 type userContextKeyType struct{}
@@ -15,27 +19,65 @@ var userContextKey = userContextKeyType{}
 
 func Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("Authorization")
-		if token == "" {
+		header := r.Header.Get("Authorization")
+		if header == "" {
 			http.Error(w, "missing token", http.StatusUnauthorized)
 			return
 		}
 
-		sessionUUID, err := uuid.Parse(token)
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		if strings.HasPrefix(token, models.APIKeyPrefix) {
+			apiKey, err := dbHelper.ValidateAPIKey(token)
+			if err != nil {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			user := &models.UserCtx{
+				UserID:   apiKey.UserID,
+				Role:     apiKey.Role,
+				APIKeyID: apiKey.ID,
+				Scopes:   apiKey.Scopes,
+			}
+			ctx := context.WithValue(r.Context(), userContextKey, user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		claimUserID, claimSessionID, err := utils.ParseJWT(token)
 		if err != nil {
 			http.Error(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
 
-		userID, err := dbHelper.ValidateSession(sessionUUID.String())
+		sessionUUID, err := uuid.Parse(claimSessionID)
+		if err != nil {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		userID, role, err := dbHelper.ValidateSession(sessionUUID.String())
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
+		if userID.String() != claimUserID {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		if err := dbHelper.TouchSession(sessionUUID.String(), r.UserAgent(), utils.ClientIP(r)); err != nil {
+			fmt.Printf("failed to update session activity: %v\n", err)
+		}
 
 		user := &models.UserCtx{
 			UserID:    userID.String(),
 			SessionID: sessionUUID.String(),
+			Role:      role,
 		}
 
 		ctx := context.WithValue(r.Context(), userContextKey, user)
@@ -43,6 +85,101 @@ func Auth(next http.Handler) http.Handler {
 	})
 }
 
+func RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !utils.RequireEmailVerification() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user := UserContext(r)
+		verified, err := dbHelper.IsUserVerified(user.UserID)
+		if err != nil {
+			http.Error(w, "failed to check email verification", http.StatusInternalServerError)
+			return
+		}
+		if !verified {
+			http.Error(w, "email address is not verified", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !UserContext(r).HasScope(scope) {
+				http.Error(w, "missing scope "+scope, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserContext(r)
+			for _, role := range roles {
+				if user.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, "forbidden", http.StatusForbidden)
+		})
+	}
+}
+
+// RequireSession keeps API keys away from account management routes.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if UserContext(r).SessionID == "" {
+			http.Error(w, "this endpoint requires an interactive session", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Workspace resolves the active workspace from the X-Workspace-ID header,
+// falling back to the user's personal workspace.
+func Workspace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := *UserContext(r)
+
+		workspaceID := r.Header.Get(models.WorkspaceHeader)
+		if workspaceID == "" {
+			personalID, err := dbHelper.GetOrCreatePersonalWorkspace(user.UserID)
+			if err != nil {
+				http.Error(w, "failed to resolve workspace", http.StatusInternalServerError)
+				return
+			}
+			workspaceID = personalID
+		} else if _, err := uuid.Parse(workspaceID); err != nil {
+			http.Error(w, "invalid workspace id", http.StatusBadRequest)
+			return
+		}
+
+		role, err := dbHelper.GetWorkspaceRole(user.UserID, workspaceID)
+		if err != nil {
+			if errors.Is(err, dbHelper.ErrWorkspaceNotFound) {
+				http.Error(w, "workspace not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to resolve workspace", http.StatusInternalServerError)
+			return
+		}
+		user.WorkspaceID = workspaceID
+		user.WorkspaceRole = role
+
+		ctx := context.WithValue(r.Context(), userContextKey, &user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func UserContext(r *http.Request) *models.UserCtx {
 	user, _ := r.Context().Value(userContextKey).(*models.UserCtx)
 	return user
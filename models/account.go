@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,lte=20,gte=6"`
+	// RevokeAPIKeys also revokes every API key; off by default so integrations keep working
+	RevokeAPIKeys bool `json:"revokeApiKeys"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type UserProfile struct {
+	ID         string     `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Email      string     `json:"email" db:"email"`
+	Timezone   string     `json:"timezone" db:"timezone"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	VerifiedAt *time.Time `json:"verifiedAt" db:"verified_at"`
+}
+
+type AccountExport struct {
+	ExportedAt time.Time        `json:"exportedAt"`
+	Profile    UserProfile      `json:"profile"`
+	Todos      []Todos          `json:"todos"`
+	Sessions   []SessionHistory `json:"sessions"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type AdminUser struct {
+	ID                 string     `json:"id" db:"id"`
+	Name               string     `json:"name" db:"name"`
+	Email              string     `json:"email" db:"email"`
+	Role               string     `json:"role" db:"role"`
+	CreatedAt          time.Time  `json:"createdAt" db:"created_at"`
+	VerifiedAt         *time.Time `json:"verifiedAt" db:"verified_at"`
+	ArchivedAt         *time.Time `json:"archivedAt" db:"archived_at"`
+	TodoCount          int        `json:"todoCount" db:"todo_count"`
+	CompletedTodoCount int        `json:"completedTodoCount" db:"completed_todo_count"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
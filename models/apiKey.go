@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	APIKeyPrefix = "todo_"
+
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=50"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=todos:read todos:write"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type APIKey struct {
+	ID         string         `json:"id" db:"id"`
+	Name       string         `json:"name" db:"name"`
+	Prefix     string         `json:"prefix" db:"prefix"`
+	Scopes     pq.StringArray `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time     `json:"expiresAt" db:"expires_at"`
+	LastUsedAt *time.Time     `json:"lastUsedAt" db:"last_used_at"`
+	CreatedAt  time.Time      `json:"createdAt" db:"created_at"`
+}
+
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyAuth struct {
+	ID     string         `db:"id"`
+	UserID string         `db:"user_id"`
+	Scopes pq.StringArray `db:"scopes"`
+	Role   string         `db:"role"`
+}
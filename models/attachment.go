@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	ID          string    `json:"id" db:"id"`
+	TodoID      string    `json:"todoId" db:"todo_id"`
+	UserID      string    `json:"userId" db:"user_id"`
+	FileName    string    `json:"fileName" db:"file_name"`
+	ContentType string    `json:"contentType" db:"content_type"`
+	SizeBytes   int64     `json:"sizeBytes" db:"size_bytes"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+type AttachmentURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type ChecklistItem struct {
+	ID          string     `json:"id" db:"id"`
+	TodoID      string     `json:"todoId" db:"todo_id"`
+	Name        string     `json:"name" db:"name"`
+	Complete    bool       `json:"complete" db:"complete"`
+	Position    int        `json:"position" db:"position"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	CompletedAt *time.Time `json:"completedAt" db:"completed_at"`
+}
+
+type CreateChecklistItemRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Name     string `json:"name" validate:"omitempty,max=100"`
+	Complete *bool  `json:"complete"`
+}
+
+type ReorderChecklistRequest struct {
+	ItemIDs []string `json:"itemIds" validate:"required,min=1,dive,uuid"`
+}
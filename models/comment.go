@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type Comment struct {
+	ID         string           `json:"id" db:"id"`
+	TodoID     string           `json:"todoId" db:"todo_id"`
+	UserID     string           `json:"userId" db:"user_id"`
+	AuthorName string           `json:"authorName" db:"author_name"`
+	Body       string           `json:"body" db:"body"`
+	Mentions   []CommentMention `json:"mentions" db:"-"`
+	CreatedAt  time.Time        `json:"createdAt" db:"created_at"`
+	EditedAt   *time.Time       `json:"editedAt" db:"edited_at"`
+}
+
+type CommentMention struct {
+	CommentID string `json:"-" db:"comment_id"`
+	UserID    string `json:"userId" db:"user_id"`
+	Name      string `json:"name" db:"name"`
+	Email     string `json:"email" db:"email"`
+}
+
+type CommentRequest struct {
+	Body string `json:"body" validate:"required,max=5000"`
+}
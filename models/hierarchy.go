@@ -0,0 +1,31 @@
+package models
+
+type TodoNode struct {
+	Todos
+	Children []*TodoNode `json:"children"`
+}
+
+type SetParentRequest struct {
+	ParentId *string `json:"parentId" validate:"omitempty,uuid"`
+}
+
+type AddDependencyRequest struct {
+	BlockedById string `json:"blockedById" validate:"required,uuid"`
+}
+
+type DependencyNode struct {
+	ID       string `json:"id" db:"id"`
+	ListID   string `json:"listId" db:"list_id"`
+	Name     string `json:"name" db:"name"`
+	Complete bool   `json:"complete" db:"complete"`
+}
+
+type DependencyEdge struct {
+	TodoID      string `json:"todoId" db:"todo_id"`
+	BlockedByID string `json:"blockedById" db:"blocked_by_id"`
+}
+
+type DependencyGraph struct {
+	Nodes []DependencyNode `json:"nodes"`
+	Edges []DependencyEdge `json:"edges"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+const (
+	ListRoleOwner  = "owner"
+	ListRoleEditor = "editor"
+	ListRoleViewer = "viewer"
+)
+
+type List struct {
+	ID          string    `json:"id" db:"id"`
+	WorkspaceID string    `json:"workspaceId" db:"workspace_id"`
+	OwnerID     string    `json:"ownerId" db:"owner_id"`
+	Name        string    `json:"name" db:"name"`
+	IsPersonal  bool      `json:"isPersonal" db:"is_personal"`
+	Role        string    `json:"role" db:"role"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+type ListRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type ListMember struct {
+	UserID    string    `json:"userId" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email" db:"email"`
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type ListInvitation struct {
+	ID         string     `json:"id" db:"id"`
+	ListID     string     `json:"listId" db:"list_id"`
+	Email      string     `json:"email" db:"email"`
+	Role       string     `json:"role" db:"role"`
+	InvitedBy  string     `json:"invitedBy" db:"invited_by"`
+	ExpiresAt  time.Time  `json:"expiresAt" db:"expires_at"`
+	AcceptedAt *time.Time `json:"acceptedAt" db:"accepted_at"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
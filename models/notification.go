@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	NotificationReminder   = "reminder"
+	NotificationMention    = "mention"
+	NotificationComment    = "comment"
+	NotificationAssignment = "assignment"
+)
+
+type Notification struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"userId" db:"user_id"`
+	ActorID   *string    `json:"actorId" db:"actor_id"`
+	Kind      string     `json:"kind" db:"kind"`
+	Title     string     `json:"title" db:"title"`
+	Body      string     `json:"body" db:"body"`
+	TodoID    *string    `json:"todoId" db:"todo_id"`
+	ReadAt    *time.Time `json:"readAt" db:"read_at"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+}
+
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	// empty on the last page
+	NextCursor string `json:"nextCursor"`
+}
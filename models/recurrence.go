@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	ScopeThis   = "this"
+	ScopeFuture = "future"
+)
+
+type TodoSeries struct {
+	ID              string    `json:"id" db:"id"`
+	UserID          string    `json:"userId" db:"user_id"`
+	RRule           string    `json:"rrule" db:"rrule"`
+	DTStart         time.Time `json:"dtstart" db:"dtstart"`
+	FirstOccurrence int       `json:"firstOccurrence" db:"first_occurrence"`
+	Name            string    `json:"name" db:"name"`
+	Description     string    `json:"description" db:"description"`
+	Priority        string    `json:"priority" db:"priority"`
+	Timezone        string    `json:"timezone" db:"timezone"`
+	CreatedAt       time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt       time.Time `json:"updatedAt" db:"updated_at"`
+
+	// occurrence of the todo the series was fetched through
+	Occurrence int `json:"occurrence" db:"occurrence"`
+	// when the occurrence after it would be due, nil once the series ends
+	NextExpiringAt *time.Time `json:"nextExpiringAt" db:"-"`
+}
+
+type SetRecurrenceRequest struct {
+	RRule string `json:"rrule" validate:"required,max=200"`
+}
+
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"required,max=64"`
+}
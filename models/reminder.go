@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+const (
+	ReminderChannelEmail   = "email"
+	ReminderChannelWebhook = "webhook"
+	ReminderChannelInApp   = "in_app"
+
+	ReminderStatusPending = "pending"
+	ReminderStatusSending = "sending"
+	ReminderStatusSent    = "sent"
+	ReminderStatusFailed  = "failed"
+)
+
+type Reminder struct {
+	ID            string     `json:"id" db:"id"`
+	TodoID        string     `json:"todoId" db:"todo_id"`
+	UserID        string     `json:"userId" db:"user_id"`
+	RemindAt      *time.Time `json:"remindAt" db:"remind_at"`
+	OffsetSeconds *int       `json:"offsetSeconds" db:"offset_seconds"`
+	Channel       string     `json:"channel" db:"channel"`
+	WebhookURL    *string    `json:"webhookUrl" db:"webhook_url"`
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	RetryAt       *time.Time `json:"retryAt" db:"retry_at"`
+	SentAt        *time.Time `json:"sentAt" db:"sent_at"`
+	DueAt         *time.Time `json:"dueAt" db:"due_at"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// CreateReminderRequest takes either remindAt or offsetSeconds before the
+// todo's expiringAt.
+type CreateReminderRequest struct {
+	RemindAt      *time.Time `json:"remindAt" validate:"required_without=OffsetSeconds,excluded_with=OffsetSeconds"`
+	OffsetSeconds *int       `json:"offsetSeconds" validate:"omitempty,min=0,max=31536000"`
+	Channel       string     `json:"channel" validate:"omitempty,oneof=email webhook in_app"`
+	WebhookURL    string     `json:"webhookUrl" validate:"required_if=Channel webhook,omitempty,url,startswith=https://,max=2000"`
+}
+
+type ReminderDelivery struct {
+	ID         string    `json:"id" db:"id"`
+	ReminderID string    `json:"reminderId" db:"reminder_id"`
+	Attempt    int       `json:"attempt" db:"attempt"`
+	Channel    string    `json:"channel" db:"channel"`
+	Error      *string   `json:"error" db:"error"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// DueReminder is a reminder claimed by the delivery worker together with
+// what it needs to build the message.
+type DueReminder struct {
+	ID         string     `db:"id"`
+	TodoID     string     `db:"todo_id"`
+	UserID     string     `db:"user_id"`
+	Email      string     `db:"email"`
+	Timezone   string     `db:"timezone"`
+	Channel    string     `db:"channel"`
+	WebhookURL *string    `db:"webhook_url"`
+	Attempts   int        `db:"attempts"`
+	TodoName   string     `db:"todo_name"`
+	ExpiringAt *time.Time `db:"expiring_at"`
+}
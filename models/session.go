@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID         string    `json:"id" db:"id"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	LastSeenAt time.Time `json:"lastSeenAt" db:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expiresAt" db:"expires_at"`
+	UserAgent  string    `json:"userAgent" db:"user_agent"`
+	ClientIP   string    `json:"clientIP" db:"client_ip"`
+	Current    bool      `json:"current" db:"-"`
+}
+
+type SessionHistory struct {
+	ID         string     `json:"id" db:"id"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	LastSeenAt time.Time  `json:"lastSeenAt" db:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expiresAt" db:"expires_at"`
+	ArchivedAt *time.Time `json:"archivedAt" db:"archived_at"`
+	UserAgent  string     `json:"userAgent" db:"user_agent"`
+	ClientIP   string     `json:"clientIP" db:"client_ip"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type Tag struct {
+	ID        string    `json:"id" db:"id"`
+	OwnerID   *string   `json:"ownerId" db:"owner_id"`
+	ListID    *string   `json:"listId" db:"list_id"`
+	Name      string    `json:"name" db:"name"`
+	Color     string    `json:"color" db:"color"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// TodoTag is a tag as attached to one todo.
+type TodoTag struct {
+	TodoID string `json:"-" db:"todo_id"`
+	ID     string `json:"id" db:"id"`
+	Name   string `json:"name" db:"name"`
+	Color  string `json:"color" db:"color"`
+}
+
+type CreateTagRequest struct {
+	Name   string `json:"name" validate:"required,max=30"`
+	Color  string `json:"color" validate:"required,hexcolor"`
+	ListId string `json:"listId" validate:"omitempty,uuid"`
+}
+
+type UpdateTagRequest struct {
+	Name  string `json:"name" validate:"required,max=30"`
+	Color string `json:"color" validate:"required,hexcolor"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type RefreshToken struct {
+	ID                string     `db:"id"`
+	SessionID         string     `db:"session_id"`
+	UserID            string     `db:"user_id"`
+	UsedAt            *time.Time `db:"used_at"`
+	ExpiresAt         time.Time  `db:"expires_at"`
+	SessionArchivedAt *time.Time `db:"archived_at"`
+	SessionActive     bool       `db:"session_active"`
+}
+
+type RefreshTokenRotation struct {
+	UserID       string
+	SessionID    string
+	RefreshToken string
+}
+
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type TwoFactorState struct {
+	Email         string     `db:"email"`
+	Secret        *string    `db:"totp_secret"`
+	PendingSecret *string    `db:"totp_pending_secret"`
+	EnabledAt     *time.Time `db:"totp_enabled_at"`
+	LastStep      int64      `db:"totp_last_step"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type LoginChallengeResponse struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	ChallengeToken    string `json:"challengeToken"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
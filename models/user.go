@@ -5,16 +5,49 @@ import "time"
 type Todos struct {
 	Id          string    `json:"id" db:"id"`
 	UserId      string    `json:"user_id" db:"user_id"`
+	ListId      string    `json:"listId" db:"list_id"`
+	AssigneeId  *string   `json:"assigneeId" db:"assignee_id"`
+	ParentId    *string   `json:"parentId" db:"parent_id"`
+	SeriesId    *string   `json:"seriesId" db:"series_id"`
+	Occurrence  *int      `json:"occurrence" db:"occurrence"`
 	Name        string    `json:"name" db:"name"`
 	Description string    `json:"description" db:"description" validate:"required,min=20"`
 	Complete    string    `json:"complete" db:"complete"`
+	Priority    string    `json:"priority" db:"priority"`
+	Position    float64   `json:"position" db:"position"`
 	ExpiringAt  time.Time `json:"expiringAt" db:"expiring_at" validate:"required"`
 	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+
+	ChecklistTotal int       `json:"checklistTotal" db:"checklist_total"`
+	ChecklistDone  int       `json:"checklistDone" db:"checklist_done"`
+	Progress       *float64  `json:"progress" db:"progress"`
+	Tags           []TodoTag `json:"tags" db:"-"`
+}
+
+type TodoFilter struct {
+	ListID      string
+	AssigneeID  string
+	Search      string
+	ExpiringAt  time.Time
+	Complete    bool
+	Tags        []string
+	MatchAnyTag bool
+	Sort        []TodoSort
+}
+
+type TodoSort struct {
+	Field string
+	Desc  bool
 }
 
 type CreateTodo struct {
+	ListId      string    `json:"listId" validate:"omitempty,uuid"`
+	AssigneeId  string    `json:"assigneeId" validate:"omitempty,uuid"`
+	ParentId    string    `json:"parentId" validate:"omitempty,uuid"`
 	Name        string    `json:"name" validate:"required,max=30"`
 	Description string    `json:"description" validate:"required,max=200"`
+	Priority    string    `json:"priority" validate:"omitempty,oneof=none low medium high urgent"`
 	ExpiringAt  time.Time `json:"expiringAt" validate:"required"`
 }
 
@@ -22,7 +55,27 @@ type UpdateTodoRequest struct {
 	Name        string `json:"name" validate:"required,max=30"`
 	Description string `json:"description" validate:"required,max=200"`
 	Complete    string `json:"complete"`
+	Priority    string `json:"priority" validate:"omitempty,oneof=none low medium high urgent"`
 	ExpiringAt  string `json:"expiringAt" validate:"required"`
+	// future also applies the change to later occurrences of a recurring todo
+	Scope string `json:"scope" validate:"omitempty,oneof=this future"`
+}
+
+type MoveTodoRequest struct {
+	AfterId *string `json:"afterId" validate:"omitempty,uuid"`
+}
+
+type AssignTodoRequest struct {
+	AssigneeId *string `json:"assigneeId" validate:"omitempty,uuid"`
+}
+
+type TodoAssignment struct {
+	ID                 string    `json:"id" db:"id"`
+	TodoID             string    `json:"todoId" db:"todo_id"`
+	AssignedBy         string    `json:"assignedBy" db:"assigned_by"`
+	PreviousAssigneeID *string   `json:"previousAssigneeId" db:"previous_assignee_id"`
+	AssigneeID         *string   `json:"assigneeId" db:"assignee_id"`
+	CreatedAt          time.Time `json:"createdAt" db:"created_at"`
 }
 
 type RegisterUser struct {
@@ -34,11 +87,50 @@ type LoginUser struct {
 	Email    string `json:"email" validate:"email"`
 	Password string `json:"password" validate:"required,lte=20,gte=6"`
 }
+
+const (
+	PriorityNone   = "none"
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type UserCtx struct {
-	UserID    string `json:"userID"`
-	SessionID string `json:"sessionID"`
+	UserID        string   `json:"userID"`
+	SessionID     string   `json:"sessionID"`
+	Role          string   `json:"role"`
+	APIKeyID      string   `json:"apiKeyID,omitempty"`
+	Scopes        []string `json:"scopes,omitempty"`
+	WorkspaceID   string   `json:"workspaceID,omitempty"`
+	WorkspaceRole string   `json:"workspaceRole,omitempty"`
 }
+
+// HasScope reports whether the credential may act on scope; sessions carry every scope.
+func (u *UserCtx) HasScope(scope string) bool {
+	if u.APIKeyID == "" {
+		return true
+	}
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 type UserAuth struct {
 	ID       string `db:"id"`
 	Password string `db:"password"`
 }
+type LoginThrottle struct {
+	Key           string     `json:"key" db:"key"`
+	Failures      int        `json:"failures" db:"failures"`
+	LastFailureAt *time.Time `json:"lastFailureAt" db:"last_failure_at"`
+	LockedUntil   *time.Time `json:"lockedUntil" db:"locked_until"`
+}
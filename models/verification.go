@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type UserVerification struct {
+	ID         string     `db:"id"`
+	Email      string     `db:"email"`
+	VerifiedAt *time.Time `db:"verified_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+
+	WorkspaceHeader = "X-Workspace-ID"
+)
+
+type Workspace struct {
+	ID         string    `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	IsPersonal bool      `json:"isPersonal" db:"is_personal"`
+	Role       string    `json:"role" db:"role"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+type WorkspaceRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type WorkspaceMember struct {
+	UserID    string    `json:"userId" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email" db:"email"`
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type AddWorkspaceMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=admin member"`
+}
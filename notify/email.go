@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nikhilpratapgit/TodoApp/mailer"
+)
+
+type EmailNotifier struct {
+	mail mailer.Mailer
+}
+
+func NewEmailNotifier(mail mailer.Mailer) *EmailNotifier {
+	return &EmailNotifier{mail: mail}
+}
+
+func (e *EmailNotifier) Notify(_ context.Context, n Notification) error {
+	if n.Email == "" {
+		return errors.New("recipient has no email address")
+	}
+	return e.mail.Send(mailer.Message{
+		To:      n.Email,
+		Subject: n.Title,
+		Body:    n.Body,
+	})
+}
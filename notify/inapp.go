@@ -0,0 +1,21 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+)
+
+type InAppNotifier struct{}
+
+func NewInAppNotifier() *InAppNotifier {
+	return &InAppNotifier{}
+}
+
+func (i *InAppNotifier) Notify(_ context.Context, n Notification) error {
+	var todoID *string
+	if n.TodoID != "" {
+		todoID = &n.TodoID
+	}
+	return dbHelper.CreateNotification(n.UserID, n.Kind, n.Title, n.Body, todoID)
+}
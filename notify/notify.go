@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nikhilpratapgit/TodoApp/mailer"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/utils"
+)
+
+type Notification struct {
+	Channel    string
+	UserID     string
+	Email      string
+	WebhookURL string
+	Kind       string
+	Title      string
+	Body       string
+	TodoID     string
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Channels sends each notification through the notifier for its channel.
+type Channels map[string]Notifier
+
+func (c Channels) Notify(ctx context.Context, n Notification) error {
+	notifier, ok := c[n.Channel]
+	if !ok {
+		return fmt.Errorf("no notifier for channel %q", n.Channel)
+	}
+	return notifier.Notify(ctx, n)
+}
+
+func FromEnv(mail mailer.Mailer) Channels {
+	return Channels{
+		models.ReminderChannelEmail: NewEmailNotifier(mail),
+		models.ReminderChannelWebhook: NewWebhookNotifier(
+			utils.GetEnvDuration("WEBHOOK_TIMEOUT", utils.DefaultWebhookTimeout),
+			utils.GetEnv("WEBHOOK_SIGNING_KEY", ""),
+		),
+		models.ReminderChannelInApp: NewInAppNotifier(),
+	}
+}
+
+// Reminder builds the notification for a due reminder, showing the due date,
+// if the todo has one, in the recipient's timezone.
+func Reminder(reminder models.DueReminder) Notification {
+	body := fmt.Sprintf("This is your reminder for %q.", reminder.TodoName)
+	if reminder.ExpiringAt != nil {
+		loc, err := time.LoadLocation(reminder.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		body = fmt.Sprintf("%q is due %s.", reminder.TodoName, reminder.ExpiringAt.In(loc).Format("Mon, 02 Jan 2006 15:04 MST"))
+	}
+	n := Notification{
+		Channel: reminder.Channel,
+		UserID:  reminder.UserID,
+		Email:   reminder.Email,
+		Kind:    models.NotificationReminder,
+		Title:   "Reminder: " + reminder.TodoName,
+		Body:    body,
+		TodoID:  reminder.TodoID,
+	}
+	if reminder.WebhookURL != nil {
+		n.WebhookURL = *reminder.WebhookURL
+	}
+	return n
+}
@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+func TestReminderBody(t *testing.T) {
+	due := time.Date(2026, 3, 1, 17, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expiringAt *time.Time
+		timezone   string
+		want       string
+	}{
+		{name: "due date in the user's timezone", expiringAt: &due, timezone: "Europe/Berlin", want: `"Pay rent" is due Sun, 01 Mar 2026 18:30 CET.`},
+		{name: "unknown timezone falls back to UTC", expiringAt: &due, timezone: "Nowhere/Else", want: `"Pay rent" is due Sun, 01 Mar 2026 17:30 UTC.`},
+		{name: "todo without a due date", timezone: "UTC", want: `This is your reminder for "Pay rent".`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := Reminder(models.DueReminder{TodoName: "Pay rent", Timezone: tt.timezone, ExpiringAt: tt.expiringAt})
+			if n.Body != tt.want {
+				t.Errorf("Body = %q, want %q", n.Body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const SignatureHeader = "X-Todo-Signature"
+
+var (
+	ErrWebhookScheme = errors.New("webhook url must use https")
+	// transport errors are replaced by this so the stored delivery error
+	// does not reveal which hosts and ports the server can reach
+	ErrWebhookUnreachable = errors.New("webhook could not be reached")
+	errWebhookAddress     = errors.New("webhook address is not allowed")
+)
+
+type WebhookNotifier struct {
+	client *http.Client
+	secret []byte
+}
+
+// NewWebhookNotifier signs request bodies with an HMAC-SHA256 of secret in
+// SignatureHeader when secret is set.
+// Requests only go to public addresses and redirects are not followed.
+func NewWebhookNotifier(timeout time.Duration, secret string) *WebhookNotifier {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	return &WebhookNotifier{
+		client: &http.Client{
+			Timeout: timeout,
+			// no proxy: the address check has to see the webhook host itself
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		secret: []byte(secret),
+	}
+}
+
+// deniedPrefixes are the special-purpose ranges of the IANA registries that
+// are not globally reachable, plus the translation ranges that lead into them.
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("fec0::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// publicAddressOnly runs after DNS resolution, so a hostname that points at
+// an internal address is refused as well.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return errWebhookAddress
+	}
+	// a zoned address never matches a prefix, so the zone is dropped first
+	ip := addrPort.Addr().Unmap().WithZone("")
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(ip) {
+			return errWebhookAddress
+		}
+	}
+	return nil
+}
+
+type webhookPayload struct {
+	Kind   string    `json:"kind"`
+	Title  string    `json:"title"`
+	Body   string    `json:"body"`
+	UserID string    `json:"userId"`
+	TodoID string    `json:"todoId,omitempty"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+func (wh *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.WebhookURL == "" {
+		return errors.New("webhook url is missing")
+	}
+	target, err := url.Parse(n.WebhookURL)
+	if err != nil || target.Scheme != "https" {
+		return ErrWebhookScheme
+	}
+	body, err := json.Marshal(webhookPayload{
+		Kind:   n.Kind,
+		Title:  n.Title,
+		Body:   n.Body,
+		UserID: n.UserID,
+		TodoID: n.TodoID,
+		SentAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(wh.secret) > 0 {
+		mac := hmac.New(sha256.New, wh.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return ErrWebhookUnreachable
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	// redirects are reported as failures rather than followed
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPublicAddressOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{address: "93.184.216.34:443", allowed: true},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443", allowed: true},
+		{address: "127.0.0.1:443"},
+		{address: "10.1.2.3:443"},
+		{address: "172.16.0.1:443"},
+		{address: "192.168.1.1:443"},
+		{address: "169.254.169.254:80"},
+		{address: "0.0.0.0:443"},
+		{address: "0.1.2.3:443"},
+		{address: "100.64.0.1:443"},
+		{address: "100.127.255.254:443"},
+		{address: "192.0.0.8:443"},
+		{address: "198.18.0.1:443"},
+		{address: "198.19.255.255:443"},
+		{address: "224.0.0.1:443"},
+		{address: "255.255.255.255:443"},
+		{address: "[::1]:443"},
+		{address: "[::]:443"},
+		{address: "[::ffff:127.0.0.1]:443"},
+		{address: "[::ffff:169.254.169.254]:443"},
+		{address: "[64:ff9b::a9fe:a9fe]:443"},
+		{address: "[2002:7f00:1::]:443"},
+		{address: "[fd00::1]:443"},
+		{address: "[fe80::1%eth0]:443"},
+		{address: "[ff02::1]:443"},
+		{address: "not-an-address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := publicAddressOnly("tcp", tt.address, nil)
+			if tt.allowed && err != nil {
+				t.Errorf("publicAddressOnly(%q) = %v, want nil", tt.address, err)
+			}
+			if !tt.allowed && !errors.Is(err, errWebhookAddress) {
+				t.Errorf("publicAddressOnly(%q) = %v, want %v", tt.address, err, errWebhookAddress)
+			}
+		})
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{name: "signed", secret: "webhook-secret"},
+		{name: "unsigned without a secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				body      []byte
+				signature string
+			)
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				signature = r.Header.Get(SignatureHeader)
+			}))
+			defer server.Close()
+
+			// the test server listens on loopback, which the real dialer refuses
+			notifier := NewWebhookNotifier(time.Second, tt.secret)
+			notifier.client = server.Client()
+			if err := notifier.Notify(context.Background(), Notification{WebhookURL: server.URL, Title: "t"}); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+
+			if tt.secret == "" {
+				if signature != "" {
+					t.Errorf("%s = %q, want none", SignatureHeader, signature)
+				}
+				return
+			}
+			mac := hmac.New(sha256.New, []byte(tt.secret))
+			mac.Write(body)
+			if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+				t.Errorf("%s = %q, want %q", SignatureHeader, signature, want)
+			}
+		})
+	}
+}
+
+func TestWebhookRefusals(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{name: "plain http", url: "http://example.com/hook", want: ErrWebhookScheme},
+		{name: "loopback host", url: server.URL, want: ErrWebhookUnreachable},
+	}
+
+	notifier := NewWebhookNotifier(time.Second, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := notifier.Notify(context.Background(), Notification{WebhookURL: tt.url})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Notify(%q) = %v, want %v", tt.url, err, tt.want)
+			}
+		})
+	}
+}
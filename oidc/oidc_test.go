@@ -0,0 +1,129 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/nikhilpratapgit/TodoApp/oidc"
+	"github.com/nikhilpratapgit/TodoApp/oidc/oidctest"
+)
+
+const redirectURL = "http://app.test/v1/oidc/oidctest/callback"
+
+// authorize runs the browser leg of the login: it opens the authorization
+// URL and returns the code and state the provider redirects back with.
+func authorize(t *testing.T, server *oidctest.Server, provider *oidc.Provider, state, nonce, verifier string) (string, string) {
+	t.Helper()
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("parse redirect: %v", err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestLoginFlow(t *testing.T) {
+	server, err := oidctest.NewServer("todo-app")
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer server.Close()
+
+	verified := oidctest.Identity{Subject: "user-1", Email: "ada@example.com", EmailVerified: true, Name: "Ada"}
+	unverified := oidctest.Identity{Subject: "user-2", Email: "bob@example.com", Name: "Bob"}
+
+	tests := []struct {
+		name     string
+		identity oidctest.Identity
+		// verifier and nonce override what the callback presents
+		verifier  string
+		nonce     string
+		reuseCode bool
+		wantErr   error
+	}{
+		{name: "verified email", identity: verified},
+		{name: "unverified email is reported as such", identity: unverified},
+		{name: "wrong code verifier", identity: verified, verifier: "not-the-verifier", wantErr: errAny},
+		{name: "wrong nonce", identity: verified, nonce: "not-the-nonce", wantErr: oidc.ErrInvalidIDToken},
+		{name: "code is single use", identity: verified, reuseCode: true, wantErr: errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider := server.Provider(redirectURL)
+			server.SetIdentity(tt.identity)
+
+			state, nonce, verifier := "state-"+tt.identity.Subject, "nonce-1", "verifier-1"
+			code, gotState := authorize(t, server, provider, state, nonce, verifier)
+			if gotState != state {
+				t.Fatalf("callback state = %q, want %q", gotState, state)
+			}
+
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			if tt.reuseCode {
+				if _, err := provider.Exchange(ctx, code, verifier); err != nil {
+					t.Fatalf("first Exchange: %v", err)
+				}
+			}
+
+			claims, err := exchangeAndVerify(ctx, provider, code, verifier, nonce)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected an error, got claims %+v", claims)
+				}
+				if tt.wantErr != errAny && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("login: %v", err)
+			}
+			want := oidc.Claims{
+				Subject:       tt.identity.Subject,
+				Email:         tt.identity.Email,
+				EmailVerified: tt.identity.EmailVerified,
+				Name:          tt.identity.Name,
+			}
+			if *claims != want {
+				t.Errorf("claims = %+v, want %+v", *claims, want)
+			}
+		})
+	}
+}
+
+func exchangeAndVerify(ctx context.Context, provider *oidc.Provider, code, verifier, nonce string) (*oidc.Claims, error) {
+	tokens, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return provider.VerifyIDToken(ctx, tokens.IDToken, nonce)
+}
+
+// errAny marks cases that only need to fail, whatever the error.
+var errAny = errors.New("any error")
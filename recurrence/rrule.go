@@ -0,0 +1,283 @@
+// Package recurrence implements the subset of RFC 5545 RRULE used for
+// recurring todos: FREQ=DAILY|WEEKLY|MONTHLY with INTERVAL, COUNT or UNTIL,
+// BYDAY for weekly rules and BYMONTHDAY for monthly rules.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+// stops rules that can never match, e.g. BYMONTHDAY=31 every 12 months from February
+const maxPeriods = 10000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRule, fmt.Sprintf(format, args...))
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// A date-only UNTIL is taken as the end of that day in loc.
+func Parse(s string, loc *time.Location) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return nil, invalid("malformed part %q", part)
+		}
+		if seen[key] {
+			return nil, invalid("%s given twice", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			if value != Daily && value != Weekly && value != Monthly {
+				return nil, invalid("unsupported FREQ %s", value)
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 1000 {
+				return nil, invalid("INTERVAL must be between 1 and 1000")
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, invalid("COUNT must be positive")
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return nil, invalid("unsupported BYDAY %s", day)
+				}
+				if !slices.Contains(rule.ByDay, weekday) {
+					rule.ByDay = append(rule.ByDay, weekday)
+				}
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, invalid("BYMONTHDAY must be between 1 and 31 or -31 and -1")
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		default:
+			return nil, invalid("unsupported part %s", key)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, invalid("FREQ is required")
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return nil, invalid("COUNT and UNTIL cannot both be set")
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return nil, invalid("BYDAY is only supported with FREQ=WEEKLY")
+	case len(rule.ByMonthDay) > 0 && rule.Freq != Monthly:
+		return nil, invalid("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return rule, nil
+}
+
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, invalid("UNTIL must look like 20261231 or 20261231T235959Z")
+}
+
+// String returns the rule in canonical RRULE form.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, weekday := range r.weekDays(time.Monday) {
+			for name, day := range weekdays {
+				if day == weekday {
+					days = append(days, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence with an index above index that is also
+// later than after, together with its index. dtstart is always occurrence 0
+// and its wall clock time in loc is kept across DST changes.
+func (r *Rule) Next(dtstart time.Time, index int, after time.Time, loc *time.Location) (time.Time, int, bool) {
+	var (
+		next  time.Time
+		found bool
+	)
+	r.each(dtstart.In(loc), func(t time.Time, i int) bool {
+		if i > index && t.After(after) {
+			next, index, found = t, i, true
+			return false
+		}
+		return true
+	})
+	return next, index, found
+}
+
+// each calls fn for every occurrence in order until fn returns false or the
+// series ends.
+func (r *Rule) each(dtstart time.Time, fn func(time.Time, int) bool) {
+	index := 0
+	emit := func(t time.Time) bool {
+		if index > 0 && !t.After(dtstart) {
+			return true
+		}
+		if r.Count > 0 && index >= r.Count {
+			return false
+		}
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		if !fn(t, index) {
+			return false
+		}
+		index++
+		return true
+	}
+	if !emit(dtstart) {
+		return
+	}
+
+	hour, minute, second := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, dtstart.Nanosecond(), dtstart.Location())
+	}
+
+	for period := 0; period < maxPeriods; period++ {
+		switch r.Freq {
+		case Daily:
+			if !emit(dtstart.AddDate(0, 0, period*r.Interval)) {
+				return
+			}
+		case Weekly:
+			// weeks start on Monday, as with the RFC default WKST=MO
+			offset := (int(dtstart.Weekday()) + 6) % 7
+			monday := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+period*r.Interval*7)
+			for _, weekday := range r.weekDays(dtstart.Weekday()) {
+				day := monday.AddDate(0, 0, (int(weekday)+6)%7)
+				if !emit(day) {
+					return
+				}
+			}
+		case Monthly:
+			first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(period*r.Interval), 1, 0, 0, 0, 0, dtstart.Location())
+			daysInMonth := first.AddDate(0, 1, -1).Day()
+			for _, day := range r.monthDays(dtstart.Day(), daysInMonth) {
+				if !emit(at(first.Year(), first.Month(), day)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// weekDays returns BYDAY from Monday to Sunday, or startDay without BYDAY.
+func (r *Rule) weekDays(startDay time.Weekday) []time.Weekday {
+	if len(r.ByDay) == 0 {
+		return []time.Weekday{startDay}
+	}
+	days := append([]time.Weekday(nil), r.ByDay...)
+	sort.Slice(days, func(i, j int) bool {
+		return (int(days[i])+6)%7 < (int(days[j])+6)%7
+	})
+	return days
+}
+
+// monthDays resolves BYMONTHDAY for one month, skipping days the month does
+// not have. Without BYMONTHDAY the day of dtstart is used.
+func (r *Rule) monthDays(startDay, daysInMonth int) []int {
+	byMonthDay := r.ByMonthDay
+	if len(byMonthDay) == 0 {
+		byMonthDay = []int{startDay}
+	}
+	days := make([]int, 0, len(byMonthDay))
+	seen := make(map[int]bool)
+	for _, day := range byMonthDay {
+		if day < 0 {
+			day = daysInMonth + day + 1
+		}
+		if day < 1 || day > daysInMonth || seen[day] {
+			continue
+		}
+		seen[day] = true
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
@@ -0,0 +1,149 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestRuleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// 2026-01-01 is a Thursday
+	dtstart := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		rule      string
+		loc       *time.Location
+		dtstart   time.Time
+		index     int
+		after     time.Time
+		want      time.Time
+		wantIndex int
+		wantOK    bool
+	}{
+		{
+			name:      "daily with interval",
+			rule:      "FREQ=DAILY;INTERVAL=2",
+			dtstart:   dtstart,
+			after:     dtstart,
+			want:      time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+			wantIndex: 1,
+			wantOK:    true,
+		},
+		{
+			name:      "slots in the past are skipped",
+			rule:      "FREQ=DAILY",
+			dtstart:   dtstart,
+			after:     time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC),
+			want:      time.Date(2026, 1, 11, 9, 0, 0, 0, time.UTC),
+			wantIndex: 10,
+			wantOK:    true,
+		},
+		{
+			name:      "weekly without BYDAY keeps the start weekday",
+			rule:      "FREQ=WEEKLY",
+			dtstart:   dtstart,
+			after:     dtstart,
+			want:      time.Date(2026, 1, 8, 9, 0, 0, 0, time.UTC),
+			wantIndex: 1,
+			wantOK:    true,
+		},
+		{
+			name:      "weekly BYDAY picks the next listed day",
+			rule:      "FREQ=WEEKLY;BYDAY=TH,MO",
+			dtstart:   dtstart,
+			after:     dtstart,
+			want:      time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			wantIndex: 1,
+			wantOK:    true,
+		},
+		{
+			name:      "biweekly BYDAY skips the Monday before dtstart",
+			rule:      "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			dtstart:   dtstart,
+			after:     dtstart,
+			want:      time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC),
+			wantIndex: 1,
+			wantOK:    true,
+		},
+		{
+			name:      "BYMONTHDAY skips months without that day",
+			rule:      "FREQ=MONTHLY;BYMONTHDAY=31",
+			dtstart:   time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			after:     time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			want:      time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC),
+			wantIndex: 1,
+			wantOK:    true,
+		},
+		{
+			name:      "negative BYMONTHDAY counts from the month end",
+			rule:      "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart:   time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			after:     time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			want:      time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC),
+			wantIndex: 1,
+			wantOK:    true,
+		},
+		{
+			name:    "COUNT ends the series",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: dtstart,
+			index:   2,
+			after:   dtstart,
+		},
+		{
+			name:      "date-only UNTIL includes that day",
+			rule:      "FREQ=DAILY;UNTIL=20260103",
+			dtstart:   dtstart,
+			index:     1,
+			after:     dtstart,
+			want:      time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:    "UNTIL ends the series",
+			rule:    "FREQ=DAILY;UNTIL=20260103",
+			dtstart: dtstart,
+			index:   2,
+			after:   dtstart,
+		},
+		{
+			name:      "wall clock time survives a DST change",
+			rule:      "FREQ=DAILY",
+			loc:       newYork,
+			dtstart:   time.Date(2026, 3, 7, 9, 0, 0, 0, newYork),
+			after:     time.Date(2026, 3, 7, 9, 0, 0, 0, newYork),
+			want:      time.Date(2026, 3, 8, 9, 0, 0, 0, newYork),
+			wantIndex: 1,
+			wantOK:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := tt.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+			rule, err := Parse(tt.rule, loc)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got, gotIndex, ok := rule.Next(tt.dtstart, tt.index, tt.after, loc)
+			if ok != tt.wantOK {
+				t.Fatalf("Next() ok = %v, want %v (got %s)", ok, tt.wantOK, got)
+			}
+			if !ok {
+				return
+			}
+			if !got.Equal(tt.want) || gotIndex != tt.wantIndex {
+				t.Errorf("Next() = %s, %d, want %s, %d", got, gotIndex, tt.want, tt.wantIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/handler"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+func adminRoutes(r chi.Router) {
+	r.Group(func(admin chi.Router) {
+		admin.Use(middleware.RequireSession)
+		admin.Use(middleware.RequireRole(models.RoleAdmin))
+		admin.Get("/users", handler.AdminListUsers)
+		admin.Get("/users/{id}", handler.AdminGetUser)
+		admin.Post("/users/{id}/archive", handler.AdminArchiveUser)
+		admin.Post("/users/{id}/unarchive", handler.AdminUnarchiveUser)
+		admin.Put("/users/{id}/role", handler.AdminSetUserRole)
+		admin.Get("/users/{id}/sessions", handler.AdminGetUserSessions)
+		admin.Delete("/users/{id}/sessions", handler.AdminRevokeUserSessions)
+		admin.Delete("/users/{id}/sessions/{sessionId}", handler.AdminRevokeUserSession)
+		admin.Get("/lockouts", handler.AdminGetLockouts)
+		admin.Delete("/lockouts", handler.AdminClearLockout)
+	})
+}
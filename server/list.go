@@ -0,0 +1,33 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/handler"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+func listRoutes(r chi.Router) {
+	r.Use(middleware.Workspace)
+	r.Group(func(read chi.Router) {
+		read.Use(middleware.RequireScope(models.ScopeTodosRead))
+		read.Get("/lists", handler.GetLists)
+		read.Get("/lists/{id}", handler.GetListById)
+		read.Get("/lists/{id}/members", handler.GetListMembers)
+	})
+	r.Group(func(write chi.Router) {
+		write.Use(middleware.RequireScope(models.ScopeTodosWrite))
+		write.Post("/lists", handler.CreateList)
+		write.Put("/lists/{id}", handler.RenameList)
+		write.Delete("/lists/{id}", handler.DeleteList)
+		write.Put("/lists/{id}/members/{userId}", handler.UpdateListMember)
+		write.Delete("/lists/{id}/members/{userId}", handler.RemoveListMember)
+	})
+	r.Group(func(sharing chi.Router) {
+		sharing.Use(middleware.RequireSession)
+		sharing.Get("/lists/{id}/invitations", handler.GetListInvitations)
+		sharing.Post("/lists/{id}/invitations", handler.InviteListMember)
+		sharing.Delete("/lists/{id}/invitations/{invitationId}", handler.RevokeListInvitation)
+		sharing.Post("/invitations/accept", handler.AcceptListInvitation)
+	})
+}
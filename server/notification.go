@@ -0,0 +1,22 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/handler"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+func notificationRoutes(r chi.Router) {
+	r.Group(func(read chi.Router) {
+		read.Use(middleware.RequireScope(models.ScopeTodosRead))
+		read.Get("/", handler.GetNotifications)
+		read.Get("/unread-count", handler.GetUnreadNotificationCount)
+	})
+	r.Group(func(write chi.Router) {
+		write.Use(middleware.RequireScope(models.ScopeTodosWrite))
+		write.Put("/read", handler.MarkAllNotificationsRead)
+		write.Put("/{id}/read", handler.MarkNotificationRead)
+		write.Delete("/{id}", handler.DeleteNotification)
+	})
+}
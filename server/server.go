@@ -28,18 +28,34 @@ func SetupRoutes() *Server {
 		//public
 		v1.Post("/register", handler.RegisterUser)
 		v1.Post("/login", handler.LoginUser)
+		v1.Post("/login/2fa", handler.LoginTwoFactor)
+		v1.Get("/oidc/{provider}/login", handler.OIDCLogin)
+		v1.Get("/oidc/{provider}/callback", handler.OIDCCallback)
+		v1.Post("/token/refresh", handler.RefreshToken)
+		v1.Post("/password/forgot", handler.ForgotPassword)
+		v1.Post("/password/reset", handler.ResetPassword)
+		v1.Get("/verify", handler.VerifyEmail)
+		v1.Post("/verify/resend", handler.ResendVerification)
+		v1.Get("/attachments/{id}/content", handler.DownloadAttachment)
 
 		v1.Group(func(v1 chi.Router) {
 			v1.Use(middleware.Auth)
 			v1.Route("/user", func(user chi.Router) {
 				user.Group(userRoutes)
 			})
+			v1.Route("/admin", func(admin chi.Router) {
+				admin.Group(adminRoutes)
+			})
+			v1.Route("/workspaces", func(workspace chi.Router) {
+				workspace.Group(workspaceRoutes)
+			})
+			v1.Route("/notifications", func(notification chi.Router) {
+				notification.Group(notificationRoutes)
+			})
 			//private
-			v1.Get("/todos", handler.GetAllTodos)
-			v1.Get("/todo/{id}", handler.GetTodoById)
-			v1.Post("/todo", handler.CreateTodo)
-			v1.Put("/todo/{id}", handler.UpdateTodoById)
-			v1.Delete("/todo/{id}", handler.DeleteTodoById)
+			v1.Group(todoRoutes)
+			v1.Group(listRoutes)
+			v1.Group(tagRoutes)
 			//v1.Get("/todos-complete", handler.CompleteTodo)
 			//v1.Get("/todos-incomplete", handler.IncompleteTodo)
 			//v1.Get("/upcoming-todos", handler.UpcomingTodos)
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/handler"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+func tagRoutes(r chi.Router) {
+	r.Use(middleware.Workspace)
+	r.Group(func(read chi.Router) {
+		read.Use(middleware.RequireScope(models.ScopeTodosRead))
+		read.Get("/tags", handler.GetTags)
+	})
+	r.Group(func(write chi.Router) {
+		write.Use(middleware.RequireScope(models.ScopeTodosWrite))
+		write.Post("/tags", handler.CreateTag)
+		write.Put("/tags/{id}", handler.UpdateTag)
+		write.Delete("/tags/{id}", handler.DeleteTag)
+		write.Put("/todo/{id}/tags/{tagId}", handler.AddTodoTag)
+		write.Delete("/todo/{id}/tags/{tagId}", handler.RemoveTodoTag)
+	})
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/handler"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+	"github.com/nikhilpratapgit/TodoApp/models"
+)
+
+func todoRoutes(r chi.Router) {
+	r.Use(middleware.Workspace)
+	r.Group(func(read chi.Router) {
+		read.Use(middleware.RequireScope(models.ScopeTodosRead))
+		read.Get("/todos", handler.GetAllTodos)
+		read.Get("/todo/{id}", handler.GetTodoById)
+		read.Get("/todo/{id}/assignments", handler.GetTodoAssignments)
+		read.Get("/todo/{id}/comments", handler.GetComments)
+		read.Get("/todo/{id}/attachments", handler.GetAttachments)
+		read.Get("/todo/{id}/checklist", handler.GetChecklist)
+		read.Get("/todo/{id}/subtree", handler.GetTodoSubtree)
+		read.Get("/todo/{id}/dependencies", handler.GetTodoDependencies)
+		read.Get("/todo/{id}/recurrence", handler.GetTodoRecurrence)
+		read.Get("/todo/{id}/reminders", handler.GetReminders)
+		read.Get("/todo/{id}/reminders/{reminderId}/deliveries", handler.GetReminderDeliveries)
+		read.Get("/todo/{id}/attachments/{attachmentId}/url", handler.GetAttachmentURL)
+	})
+	r.Group(func(write chi.Router) {
+		write.Use(middleware.RequireScope(models.ScopeTodosWrite))
+		write.With(middleware.RequireVerifiedEmail).Post("/todo", handler.CreateTodo)
+		write.Put("/todo/{id}", handler.UpdateTodoById)
+		write.Put("/todo/{id}/position", handler.MoveTodo)
+		write.Delete("/todo/{id}", handler.DeleteTodoById)
+		write.Put("/todo/{id}/assignee", handler.AssignTodo)
+		write.Post("/todo/{id}/comments", handler.CreateComment)
+		write.Put("/todo/{id}/comments/{commentId}", handler.UpdateComment)
+		write.Delete("/todo/{id}/comments/{commentId}", handler.DeleteComment)
+		write.Post("/todo/{id}/attachments", handler.UploadAttachment)
+		write.Delete("/todo/{id}/attachments/{attachmentId}", handler.DeleteAttachment)
+		write.Post("/todo/{id}/checklist", handler.CreateChecklistItem)
+		write.Put("/todo/{id}/checklist/order", handler.ReorderChecklist)
+		write.Put("/todo/{id}/checklist/{itemId}", handler.UpdateChecklistItem)
+		write.Delete("/todo/{id}/checklist/{itemId}", handler.DeleteChecklistItem)
+		write.Put("/todo/{id}/parent", handler.SetTodoParent)
+		write.Post("/todo/{id}/dependencies", handler.AddTodoDependency)
+		write.Delete("/todo/{id}/dependencies/{blockedById}", handler.RemoveTodoDependency)
+		write.Put("/todo/{id}/recurrence", handler.SetTodoRecurrence)
+		write.Delete("/todo/{id}/recurrence", handler.StopTodoRecurrence)
+		write.Post("/todo/{id}/reminders", handler.CreateReminder)
+		write.Delete("/todo/{id}/reminders/{reminderId}", handler.DeleteReminder)
+	})
+}
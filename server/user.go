@@ -3,10 +3,29 @@ package server
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/nikhilpratapgit/TodoApp/handler"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
 )
 
 func userRoutes(r chi.Router) {
 	r.Group(func(user chi.Router) {
+		user.Use(middleware.RequireSession)
+		user.Delete("/", handler.DeleteAccount)
 		user.Delete("/logout", handler.Logout)
+		user.Put("/password", handler.ChangePassword)
+		user.Put("/email", handler.ChangeEmail)
+		user.Put("/timezone", handler.ChangeTimezone)
+		user.Get("/export", handler.ExportAccount)
+		user.Get("/api-keys", handler.GetAPIKeys)
+		user.Post("/api-keys", handler.CreateAPIKey)
+		user.Delete("/api-keys/{id}", handler.RevokeAPIKey)
+		user.Route("/2fa", func(twoFactor chi.Router) {
+			twoFactor.Post("/setup", handler.SetupTwoFactor)
+			twoFactor.Post("/confirm", handler.ConfirmTwoFactor)
+			twoFactor.Post("/disable", handler.DisableTwoFactor)
+		})
+		user.Get("/sessions", handler.GetSessions)
+		user.Delete("/sessions", handler.RevokeOtherSessions)
+		user.Get("/sessions/{id}", handler.GetSessionById)
+		user.Delete("/sessions/{id}", handler.RevokeSession)
 	})
 }
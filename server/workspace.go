@@ -0,0 +1,18 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/TodoApp/handler"
+	"github.com/nikhilpratapgit/TodoApp/middleware"
+)
+
+func workspaceRoutes(r chi.Router) {
+	r.Group(func(workspace chi.Router) {
+		workspace.Use(middleware.RequireSession)
+		workspace.Get("/", handler.GetWorkspaces)
+		workspace.Post("/", handler.CreateWorkspace)
+		workspace.Get("/{id}/members", handler.GetWorkspaceMembers)
+		workspace.Post("/{id}/members", handler.AddWorkspaceMember)
+		workspace.Delete("/{id}/members/{userId}", handler.RemoveWorkspaceMember)
+	})
+}
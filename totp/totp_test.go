@@ -0,0 +1,101 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// base32 of the ASCII seed "12345678901234567890" used by RFC 6238
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// RFC 6238 Appendix B, SHA1 column, cut to the six digits this package uses.
+func TestCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		wantOK bool
+	}{
+		{name: "current step", offset: 0, wantOK: true},
+		{name: "one step behind", offset: -1, wantOK: true},
+		{name: "one step ahead", offset: 1, wantOK: true},
+		{name: "two steps behind", offset: -2},
+		{name: "two steps ahead", offset: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, current+tt.offset)
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+			step, ok := Validate(rfcSecret, code, now)
+			if ok != tt.wantOK {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("Validate step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateAfterRejectsReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+	code, err := Code(rfcSecret, current)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	previous, err := Code(rfcSecret, current-1)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		wantOK   bool
+	}{
+		{name: "first use", code: code, lastStep: current - 1, wantOK: true},
+		{name: "same code again", code: code, lastStep: current},
+		{name: "older code after a newer one", code: previous, lastStep: current},
+		{name: "older code still unused", code: previous, lastStep: current - 2, wantOK: true},
+		{name: "wrong code", code: "000000", lastStep: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ValidateAfter(rfcSecret, tt.code, now, tt.lastStep); ok != tt.wantOK {
+				t.Errorf("ValidateAfter ok = %v, want %v", ok, tt.wantOK)
+			}
+		})
+	}
+}
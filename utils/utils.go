@@ -1,13 +1,25 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -20,6 +32,51 @@ import (
 
 var Validate = validator.New()
 
+var (
+	mentionPattern  = regexp.MustCompile(`(?:^|[^\w@])@([\w.+-]+@[\w-]+(?:\.[\w-]+)+)`)
+	codeSpanPattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+)
+
+const (
+	RefreshTokenTTL = 30 * 24 * time.Hour
+
+	DefaultSessionLifetime      = 30 * 24 * time.Hour
+	DefaultSessionIdleTimeout   = 7 * 24 * time.Hour
+	DefaultSessionSweepInterval = 10 * time.Minute
+
+	DefaultPasswordResetTTL     = time.Hour
+	DefaultEmailVerificationTTL = 24 * time.Hour
+
+	DefaultAccountPurgeGracePeriod = 30 * 24 * time.Hour
+	DefaultAccountPurgeInterval    = time.Hour
+
+	DefaultLoginChallengeTTL = 5 * time.Minute
+	DefaultOIDCStateTTL      = 10 * time.Minute
+	DefaultListInvitationTTL = 7 * 24 * time.Hour
+	RecoveryCodeCount        = 10
+
+	DefaultLoginMaxFailuresPerEmail  = 5
+	DefaultLoginMaxFailuresPerIP     = 20
+	DefaultLoginMaxTwoFactorFailures = 10
+	DefaultLoginLockoutDuration      = 15 * time.Minute
+
+	DefaultVerificationMaxResendsPerEmail = 3
+	DefaultVerificationMaxResendsPerIP    = 20
+
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+
+	DefaultAttachmentMaxBytes = 10 << 20
+	DefaultAttachmentURLTTL   = 5 * time.Minute
+
+	DefaultReminderInterval    = 30 * time.Second
+	DefaultReminderBatchSize   = 20
+	DefaultReminderMaxAttempts = 5
+	DefaultReminderBackoff     = time.Minute
+	DefaultReminderLease       = 10 * time.Minute
+	DefaultWebhookTimeout      = 10 * time.Second
+)
+
 type Error struct {
 	StatusCode    int    `json:"statusCode"`
 	Error         string `json:"error"`
@@ -43,7 +100,7 @@ func RespondJSON(w http.ResponseWriter, statusCode int, body interface{}) {
 	w.WriteHeader(statusCode)
 	if body != nil {
 		if err := EncodeJSONBody(w, body); err != nil {
-			fmt.Println("Failed to respond JSON with error: %v", err)
+			fmt.Printf("Failed to respond JSON with error: %v", err)
 		}
 	}
 }
@@ -78,6 +135,64 @@ func ParseBool(str string) bool {
 	}
 	return true
 }
+func ParseLimitOffset(r *http.Request) (int, int, error) {
+	limit, offset := DefaultPageLimit, 0
+	if str := r.URL.Query().Get("limit"); str != "" {
+		l, err := strconv.Atoi(str)
+		if err != nil || l <= 0 {
+			return 0, 0, errors.New("limit must be a positive number")
+		}
+		limit = min(l, MaxPageLimit)
+	}
+	if str := r.URL.Query().Get("offset"); str != "" {
+		o, err := strconv.Atoi(str)
+		if err != nil || o < 0 {
+			return 0, 0, errors.New("offset must not be negative")
+		}
+		offset = o
+	}
+	return limit, offset, nil
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor makes an opaque keyset cursor for rows ordered by
+// (createdAt, id).
+func EncodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+func DecodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil || Validate.Var(id, "uuid") != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return t, id, nil
+}
+
+// ParseMentions returns the lowercased, de-duplicated emails mentioned as
+// @user@example.com in a markdown body, ignoring code spans and blocks.
+func ParseMentions(body string) []string {
+	body = codeSpanPattern.ReplaceAllString(body, " ")
+	seen := make(map[string]bool)
+	emails := make([]string, 0)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		email := strings.ToLower(strings.TrimRight(match[1], "."))
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
 func ParseExpiringAt(str string) (time.Time, error) {
 	var date time.Time
 	if str != "" {
@@ -101,6 +216,194 @@ func GenerateJWT(usrID, sessionID string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
 }
+func ParseJWT(tokenString string) (userID, sessionID string, err error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", "", errors.New("invalid token")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return "", "", errors.New("token expired")
+	}
+	userID, _ = claims["userId"].(string)
+	sessionID, _ = claims["sessionId"].(string)
+	if userID == "" || sessionID == "" {
+		return "", "", errors.New("invalid token claims")
+	}
+	return userID, sessionID, nil
+}
+func GenerateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+func GenerateRecoveryCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+	return code[:4] + "-" + code[4:], nil
+}
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+func urlSignature(path string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("URL_SIGNING_KEY")))
+	fmt.Fprintf(mac, "%s\n%d", path, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignURL returns an absolute link to path that VerifySignedURL accepts
+// until expiresAt.
+func SignURL(path string, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+	return fmt.Sprintf("%s%s?expires=%d&signature=%s", AppBaseURL(), path, expires, urlSignature(path, expires))
+}
+func VerifySignedURL(r *http.Request) bool {
+	// without a key anyone could compute the signature
+	if os.Getenv("URL_SIGNING_KEY") == "" {
+		return false
+	}
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	expected := urlSignature(r.URL.Path, expires)
+	return hmac.Equal([]byte(expected), []byte(r.URL.Query().Get("signature")))
+}
+
+// trustedProxies parses TRUSTED_PROXIES, a comma separated list of IPs or CIDRs.
+var trustedProxies = sync.OnceValue(func() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0)
+	for _, entry := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		fmt.Printf("invalid entry in TRUSTED_PROXIES: %q, ignoring it\n", entry)
+	}
+	return prefixes
+})
+
+func isTrustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies() {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the peer, or behind trusted proxies the right-most untrusted X-Forwarded-For hop.
+func ClientIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !isTrustedProxy(remote) {
+		return remote
+	}
+
+	hops := make([]string, 0)
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !isTrustedProxy(hops[i]) {
+			return hops[i]
+		}
+	}
+	if len(hops) > 0 {
+		return hops[0]
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		return realIP
+	}
+	return remote
+}
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		fmt.Printf("invalid duration for %s: %q, using %s\n", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+func GetEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		fmt.Printf("invalid bool for %s: %q, using %t\n", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		fmt.Printf("invalid int for %s: %q, using %d\n", key, value, fallback)
+		return fallback
+	}
+	return i
+}
+func RequireEmailVerification() bool {
+	return GetEnvBool("REQUIRE_EMAIL_VERIFICATION", false)
+}
+func SessionLifetime() time.Duration {
+	return GetEnvDuration("SESSION_LIFETIME", DefaultSessionLifetime)
+}
+func SessionIdleTimeout() time.Duration {
+	return GetEnvDuration("SESSION_IDLE_TIMEOUT", DefaultSessionIdleTimeout)
+}
+func GetEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+func AppBaseURL() string {
+	return strings.TrimRight(GetEnv("APP_BASE_URL", "http://localhost:8080"), "/")
+}
 func GoDotEnvVariable(key string) string {
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nikhilpratapgit/TodoApp/blob"
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+)
+
+func StartAccountPurger(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func() {
+		purgeAccounts(ctx)
+	})
+}
+
+func purgeAccounts(ctx context.Context) {
+	purged, storageKeys, err := dbHelper.PurgeDeletedAccounts()
+	if err != nil {
+		fmt.Printf("failed to purge deleted accounts: %v\n", err)
+		return
+	}
+	for _, key := range storageKeys {
+		if err := blob.Default.Delete(ctx, key); err != nil {
+			fmt.Printf("failed to delete attachment blob %s: %v\n", key, err)
+		}
+	}
+	if purged > 0 {
+		fmt.Printf("purged data of %d deleted accounts\n", purged)
+	}
+}
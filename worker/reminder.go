@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+	"github.com/nikhilpratapgit/TodoApp/models"
+	"github.com/nikhilpratapgit/TodoApp/notify"
+)
+
+type ReminderConfig struct {
+	BatchSize   int
+	MaxAttempts int
+	Backoff     time.Duration
+	// Lease must outlast delivering a whole batch, or another instance
+	// may claim the same reminders again
+	Lease time.Duration
+}
+
+// StartReminderDispatcher is safe to run in several instances at once,
+// each batch only claims reminders no other instance holds.
+func StartReminderDispatcher(ctx context.Context, interval time.Duration, notifier notify.Notifier, config ReminderConfig) {
+	runEvery(ctx, interval, func() {
+		dispatchReminders(ctx, notifier, config)
+	})
+}
+
+func dispatchReminders(ctx context.Context, notifier notify.Notifier, config ReminderConfig) {
+	for ctx.Err() == nil {
+		claimed, err := dbHelper.DeliverDueReminders(config.BatchSize, config.MaxAttempts, config.Backoff, config.Lease,
+			func(reminder models.DueReminder) error {
+				return notifier.Notify(ctx, notify.Reminder(reminder))
+			})
+		if err != nil {
+			fmt.Printf("failed to deliver reminders: %v\n", err)
+			return
+		}
+		if claimed > 0 {
+			fmt.Printf("processed %d due reminders\n", claimed)
+		}
+		if claimed == 0 || claimed < config.BatchSize {
+			return
+		}
+	}
+}
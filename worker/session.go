@@ -0,0 +1,24 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nikhilpratapgit/TodoApp/database/dbHelper"
+)
+
+func StartSessionSweeper(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, sweepSessions)
+}
+
+func sweepSessions() {
+	archived, err := dbHelper.ArchiveStaleSessions()
+	if err != nil {
+		fmt.Printf("failed to archive stale sessions: %v\n", err)
+		return
+	}
+	if archived > 0 {
+		fmt.Printf("archived %d stale sessions\n", archived)
+	}
+}